@@ -25,6 +25,10 @@ type file struct {
 	forceFlush bool
 }
 
+// File creates a backend appending events to the file at path. Events are
+// delimited by the layout, see layout.Framing. If bufferSize is not negative,
+// events are buffered, with the buffer being flushed before an event that
+// does not fit, such that events are never split between writes.
 func File(
 	lvl backend.Level,
 	path string,
@@ -42,7 +46,7 @@ func File(
 	var out io.Writer = f
 	if bufferSize >= 0 {
 		buf = bufio.NewWriterSize(f, bufferSize)
-		out = buf
+		out = alignedWriter{buf}
 	}
 
 	l, err := layout(out)
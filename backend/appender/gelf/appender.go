@@ -0,0 +1,228 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/appender/internal/netappender"
+	"github.com/urso/ecslog/backend/layout"
+)
+
+// Appender sends log messages to a GELF input via UDP or TCP. The appender
+// should be used with layout.GELF.
+//
+// Messages sent via UDP can be compressed, and are split into GELF chunks if
+// they exceed the configured chunk size. Messages sent via TCP are terminated
+// by a null byte. Compression is not supported by GELF TCP inputs.
+type Appender struct {
+	base *netappender.Appender
+
+	network     string
+	compression Compression
+	chunkSize   int
+
+	buf  bytes.Buffer
+	rand *rand.Rand
+}
+
+// Compression selects the compression algorithm used for UDP messages.
+type Compression uint8
+
+const (
+	CompressNone Compression = iota
+	CompressGZip
+	CompressZlib
+)
+
+// DefaultChunkSize is used if chunk size is 0. It is the recommended chunk
+// size if messages are sent over the internet.
+const DefaultChunkSize = 1420
+
+const (
+	chunkHeaderSize = 12
+	maxChunks       = 128
+)
+
+var chunkMagic = []byte{0x1e, 0x0f}
+
+// appenderWriter provides the Write operation required by the Layout instance.
+// It is used so to not Export an unsafe Write operation in the public API of
+// Appender.
+type appenderWriter Appender
+
+// NewAppender creates a GELF appender connecting to address. The network must
+// be one of "udp", "udp4", "udp6", "tcp", "tcp4", or "tcp6".
+// The chunk size is the maximum size of an UDP datagram, including the chunk
+// header. DefaultChunkSize is used if chunkSize is 0.
+func NewAppender(
+	lvl backend.Level,
+	network, address string,
+	layout layout.Factory,
+	compression Compression,
+	chunkSize int,
+) (*Appender, error) {
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if chunkSize <= chunkHeaderSize {
+		return nil, fmt.Errorf("GELF chunk size %v is too small", chunkSize)
+	}
+
+	switch network {
+	case "udp", "udp4", "udp6":
+	case "tcp", "tcp4", "tcp6":
+		if compression != CompressNone {
+			return nil, errors.New("GELF over TCP does not support compression")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported network '%v'", network)
+	}
+
+	if compression > CompressZlib {
+		return nil, fmt.Errorf("unknown compression %v", compression)
+	}
+
+	a := &Appender{
+		network:     network,
+		compression: compression,
+		chunkSize:   chunkSize,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	base, err := netappender.New(lvl, layout, (*appenderWriter)(a), func() (net.Conn, error) {
+		return net.Dial(network, address)
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.base = base
+	return a, nil
+}
+
+// Close closes the connection. Messages logged after Close are dropped.
+func (a *Appender) Close() error {
+	return a.base.Close()
+}
+
+func (a *Appender) For(name string) backend.Backend {
+	return a
+}
+
+func (a *Appender) IsEnabled(lvl backend.Level) bool {
+	return a.base.IsEnabled(lvl)
+}
+
+func (a *Appender) UseContext() bool {
+	return a.base.UseContext()
+}
+
+func (a *Appender) Log(msg backend.Message) {
+	a.base.Log(msg)
+}
+
+func (a *Appender) isUDP() bool {
+	return a.network[:3] == "udp"
+}
+
+func (a *Appender) sendTCP(conn net.Conn, msg []byte) (int, error) {
+	a.buf.Reset()
+	a.buf.Write(msg)
+	a.buf.WriteByte(0)
+	return conn.Write(a.buf.Bytes())
+}
+
+func (a *Appender) sendUDP(conn net.Conn, msg []byte) (int, error) {
+	if len(msg) > a.chunkSize {
+		return a.sendChunks(conn, msg)
+	}
+	return conn.Write(msg)
+}
+
+// sendChunks splits msg into GELF chunks. Each chunk is prefixed with the
+// chunk magic bytes, the message ID, the sequence number, and the total
+// number of chunks. The number of bytes written includes the chunk headers.
+func (a *Appender) sendChunks(conn net.Conn, msg []byte) (int, error) {
+	payloadSize := a.chunkSize - chunkHeaderSize
+	count := (len(msg) + payloadSize - 1) / payloadSize
+
+	chunk := make([]byte, a.chunkSize)
+	copy(chunk, chunkMagic)
+	binary.BigEndian.PutUint64(chunk[2:], a.rand.Uint64())
+	chunk[11] = byte(count)
+
+	written := 0
+	for seq := 0; seq < count; seq++ {
+		chunk[10] = byte(seq)
+		n := copy(chunk[chunkHeaderSize:], msg[seq*payloadSize:])
+		n, err := conn.Write(chunk[:chunkHeaderSize+n])
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// compress writes the compressed message into the appenders buffer.
+func (a *Appender) compress(msg []byte) ([]byte, error) {
+	a.buf.Reset()
+
+	var w io.WriteCloser
+	switch a.compression {
+	case CompressGZip:
+		w = gzip.NewWriter(&a.buf)
+	case CompressZlib:
+		w = zlib.NewWriter(&a.buf)
+	default:
+		return msg, nil
+	}
+
+	if _, err := w.Write(msg); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return a.buf.Bytes(), nil
+}
+
+func (w *appenderWriter) Write(msg []byte) (int, error) {
+	a := (*Appender)(w)
+
+	data := msg
+	if a.isUDP() {
+		var err error
+		if data, err = a.compress(msg); err != nil {
+			return 0, err
+		}
+		if len(data) > maxChunks*(a.chunkSize-chunkHeaderSize) {
+			return 0, ErrMessageTooLarge
+		}
+	}
+
+	err := a.base.Send(func(conn net.Conn) (int, error) {
+		if a.isUDP() {
+			return a.sendUDP(conn, data)
+		}
+		return a.sendTCP(conn, data)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(msg), nil
+}
@@ -0,0 +1,227 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package gelf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/layout"
+)
+
+func TestUDP(t *testing.T) {
+	long := strings.Repeat("long message ", 200)
+
+	cases := map[string]struct {
+		compression Compression
+		message     string
+	}{
+		"plain":         {CompressNone, "hello"},
+		"plain chunked": {CompressNone, long},
+		"gzip":          {CompressGZip, "hello"},
+		"gzip chunked":  {CompressGZip, long + randomText(2000)},
+		"zlib chunked":  {CompressZlib, long + randomText(2000)},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			a, err := NewAppender(backend.Trace, "udp", conn.LocalAddr().String(),
+				layout.GELF("test-host", nil), test.compression, 256)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer a.Close()
+
+			a.Log(testMessage(test.message))
+
+			event := decode(t, readUDPMessage(t, conn))
+			checkEvent(t, event, test.message)
+		})
+	}
+}
+
+func TestTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer conn.Close()
+
+		var msgs []string
+		r := bufio.NewReader(conn)
+		for len(msgs) < 2 {
+			msg, err := r.ReadString(0)
+			if err != nil {
+				break
+			}
+			msgs = append(msgs, strings.TrimSuffix(msg, "\x00"))
+		}
+		received <- msgs
+	}()
+
+	a, err := NewAppender(backend.Trace, "tcp", l.Addr().String(), layout.GELF("test-host", nil), CompressNone, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	a.Log(testMessage("first"))
+	a.Log(testMessage("second"))
+
+	select {
+	case msgs := <-received:
+		if len(msgs) != 2 {
+			t.Fatalf("expected 2 messages, got %v", len(msgs))
+		}
+		checkEvent(t, decode(t, []byte(msgs[0])), "first")
+		checkEvent(t, decode(t, []byte(msgs[1])), "second")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for messages")
+	}
+}
+
+func TestTCPCompressionUnsupported(t *testing.T) {
+	_, err := NewAppender(backend.Trace, "tcp", "127.0.0.1:0", layout.GELF("test-host", nil), CompressGZip, 0)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func testMessage(msg string) backend.Message {
+	ctx := diag.NewContext(nil, nil)
+	ctx.AddField(diag.String("custom", "value"))
+	ctx.AddField(diag.Field{Key: "host.hostname", Value: diag.ValString("localhost"), Standardized: true})
+
+	return backend.Message{
+		Name:    "test",
+		Level:   backend.Error,
+		Caller:  backend.GetCaller(0),
+		Message: msg,
+		Context: ctx,
+		Causes:  []error{errors.New("oops")},
+	}
+}
+
+func checkEvent(t *testing.T, event map[string]interface{}, msg string) {
+	expected := map[string]interface{}{
+		"version":        "1.1",
+		"host":           "test-host",
+		"short_message":  msg,
+		"full_message":   msg + "\n\toops\n",
+		"level":          float64(3),
+		"_logger":        "test",
+		"_custom":        "value",
+		"_host.hostname": "localhost",
+	}
+	for k, v := range expected {
+		if event[k] != v {
+			t.Errorf("field %v: expected %q, got %q", k, v, event[k])
+		}
+	}
+	if _, ok := event["timestamp"].(float64); !ok {
+		t.Errorf("missing timestamp")
+	}
+}
+
+func decode(t *testing.T, msg []byte) map[string]interface{} {
+	var r io.Reader = bytes.NewReader(msg)
+	var err error
+	switch {
+	case bytes.HasPrefix(msg, []byte{0x1f, 0x8b}):
+		r, err = gzip.NewReader(r)
+	case msg[0] == 0x78:
+		r, err = zlib.NewReader(r)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(raw, &event); err != nil {
+		t.Fatalf("invalid message %q: %v", raw, err)
+	}
+	return event
+}
+
+// readUDPMessage reads datagrams until a complete message has been received.
+// Chunks are reassembled.
+func readUDPMessage(t *testing.T, conn net.PacketConn) []byte {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var chunks [][]byte
+	received := 0
+	buf := make([]byte, 65536)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		datagram := buf[:n]
+		if !bytes.HasPrefix(datagram, chunkMagic) {
+			return append([]byte(nil), datagram...)
+		}
+		if n > 256 {
+			t.Fatalf("chunk size %v exceeds limit", n)
+		}
+
+		seq, count := int(datagram[10]), int(datagram[11])
+		if chunks == nil {
+			chunks = make([][]byte, count)
+		}
+		chunks[seq] = append([]byte(nil), datagram[chunkHeaderSize:]...)
+		if received++; received == count {
+			return bytes.Join(chunks, nil)
+		}
+	}
+}
+
+// randomText creates incompressible text, so to enforce chunking of
+// compressed messages.
+func randomText(n int) string {
+	var sb strings.Builder
+	state := uint32(42)
+	for i := 0; i < n; i++ {
+		state = state*1664525 + 1013904223
+		sb.WriteByte('a' + byte(state>>24)%26)
+	}
+	return sb.String()
+}
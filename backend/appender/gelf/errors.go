@@ -0,0 +1,13 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package gelf
+
+import "errors"
+
+// ErrMessageTooLarge indicates that a message could not be sent, because it
+// requires more than 128 chunks. The message will be lost.
+var ErrMessageTooLarge = errors.New("GELF message exceeds maximum number of chunks")
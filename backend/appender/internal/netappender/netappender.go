@@ -0,0 +1,134 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+// Package netappender provides the connection handling shared by the
+// appenders sending log messages over the network.
+package netappender
+
+import (
+	"io"
+	"net"
+	"sync"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/layout"
+)
+
+// Appender serializes log messages via a layout and sends them over a
+// network connection. The layout writes into the io.Writer passed to New,
+// which is expected to call Send.
+//
+// The appender reconnects if sending fails before any data has been written,
+// and retries sending the message once. Messages that fail after being
+// partially written are dropped, so to not send the same data twice. The
+// next message triggers a reconnect if sending fails.
+type Appender struct {
+	lvl    backend.Level
+	layout layout.Layout
+	dial   func() (net.Conn, error)
+
+	mu     sync.Mutex
+	closed bool
+	conn   net.Conn
+}
+
+// New creates an appender and connects via dial. The layout is created with
+// out as output.
+func New(
+	lvl backend.Level,
+	factory layout.Factory,
+	out io.Writer,
+	dial func() (net.Conn, error),
+) (*Appender, error) {
+	l, err := factory(out)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Appender{lvl: lvl, layout: l, dial: dial}
+	if err := a.connect(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Close closes the connection. Messages logged after Close are dropped.
+func (a *Appender) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil
+	}
+
+	a.closed = true
+	if a.conn == nil {
+		return nil
+	}
+	err := a.conn.Close()
+	a.conn = nil
+	return err
+}
+
+func (a *Appender) IsEnabled(lvl backend.Level) bool {
+	return lvl >= a.lvl
+}
+
+func (a *Appender) UseContext() bool {
+	return a.layout.UseContext()
+}
+
+func (a *Appender) Log(msg backend.Message) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return
+	}
+	a.layout.Log(msg)
+}
+
+// Send passes the connection to write. write reports the number of bytes
+// written to the connection. If the connection has been closed, or writing
+// fails before any data has been written, the connection is re-established
+// and write is called once more. If writing fails after parts of the message
+// have been written, the message is dropped and the connection is closed, so
+// the next message is sent over a new connection. Send must only be called
+// from within the layout.
+func (a *Appender) Send(write func(conn net.Conn) (int, error)) error {
+	for attempt := 0; ; attempt++ {
+		if a.conn == nil {
+			if err := a.connect(); err != nil {
+				return err
+			}
+		}
+
+		n, err := write(a.conn)
+		if err == nil {
+			return nil
+		}
+
+		a.disconnect()
+		if n > 0 || attempt > 0 {
+			return err
+		}
+	}
+}
+
+func (a *Appender) connect() error {
+	conn, err := a.dial()
+	if err != nil {
+		return err
+	}
+	a.conn = conn
+	return nil
+}
+
+func (a *Appender) disconnect() {
+	if a.conn != nil {
+		a.conn.Close()
+		a.conn = nil
+	}
+}
@@ -0,0 +1,105 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package netappender
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/layout"
+)
+
+type testConn struct {
+	net.Conn
+	closed bool
+}
+
+func (c *testConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSendRetry(t *testing.T) {
+	errWrite := errors.New("write failed")
+
+	cases := map[string]struct {
+		// bytes written and failure per write call
+		results []int
+		fails   []bool
+
+		wantErr   bool
+		wantCalls int
+		wantDials int
+	}{
+		"success": {
+			results: []int{10}, fails: []bool{false},
+			wantCalls: 1, wantDials: 1,
+		},
+		"retry if nothing written": {
+			results: []int{0, 10}, fails: []bool{true, false},
+			wantCalls: 2, wantDials: 2,
+		},
+		"retry only once": {
+			results: []int{0, 0}, fails: []bool{true, true},
+			wantErr: true, wantCalls: 2, wantDials: 2,
+		},
+		"drop partial write": {
+			results: []int{5}, fails: []bool{true},
+			wantErr: true, wantCalls: 1, wantDials: 1,
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var conns []*testConn
+			a, err := New(backend.Trace, layout.Text(false), ioutil.Discard, func() (net.Conn, error) {
+				c := &testConn{}
+				conns = append(conns, c)
+				return c, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			calls := 0
+			err = a.Send(func(conn net.Conn) (int, error) {
+				i := calls
+				calls++
+				if conn != conns[len(conns)-1] {
+					t.Errorf("call %v: unexpected connection", i)
+				}
+				if test.fails[i] {
+					return test.results[i], errWrite
+				}
+				return test.results[i], nil
+			})
+
+			if test.wantErr != (err != nil) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if calls != test.wantCalls {
+				t.Errorf("got %v calls, want %v", calls, test.wantCalls)
+			}
+			if len(conns) != test.wantDials {
+				t.Errorf("got %v dials, want %v", len(conns), test.wantDials)
+			}
+			if last := conns[len(conns)-1]; last.closed != test.wantErr {
+				t.Errorf("connection closed: %v, want %v", last.closed, test.wantErr)
+			}
+
+			// the next message is sent over a new connection if sending failed
+			a.Send(func(conn net.Conn) (int, error) { return 1, nil })
+			if test.wantErr && len(conns) != test.wantDials+1 {
+				t.Errorf("expected reconnect after failure, got %v dials", len(conns))
+			}
+		})
+	}
+}
@@ -42,8 +42,6 @@ type Rotator interface {
 var _ Rotator = (*Appender)(nil)
 var _ FileStater = (*Appender)(nil)
 
-var newline = []byte("\n")
-
 // FileStater is used by the trigger and strategy to query the state of the
 // current active log file.
 type FileStater interface {
@@ -217,12 +215,10 @@ func (a *appenderWriter) Write(b []byte) (int, error) {
 		}
 	}
 
+	// Events are delimited by the layout, see layout.Framing. Each event is
+	// written with a single call, such that files are rotated between events.
 	n, err := a.file.Write(b)
 	a.stat.Size += uint64(n)
-	if err == nil {
-		a.file.Write(newline)
-		a.stat.Size++
-	}
 	return n, err
 }
 
@@ -0,0 +1,153 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package syslog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/appender/internal/netappender"
+	"github.com/urso/ecslog/backend/layout"
+)
+
+// Appender sends log messages to a syslog daemon. The appender should be
+// used with layout.Syslog.
+//
+// Messages are sent as is over datagram sockets (unixgram, UDP). Stream
+// sockets (unix, TCP) use octet-counting framing as defined in RFC 6587.
+// If sending fails before any data has been written, the appender reconnects
+// and retries sending the message once. Partially written messages are
+// dropped, and the next message is sent over a new connection.
+type Appender struct {
+	base *netappender.Appender
+
+	network string
+	address string
+	stream  bool
+	buf     bytes.Buffer
+}
+
+// localSockets lists the well known socket paths of the local syslog daemon.
+var localSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// appenderWriter provides the Write operation required by the Layout instance.
+// It is used so to not Export an unsafe Write operation in the public API of
+// Appender.
+type appenderWriter Appender
+
+// NewAppender creates a syslog appender connecting to address. The network
+// must be one of "unixgram", "unix", "udp", "udp4", "udp6", "tcp", "tcp4",
+// or "tcp6". If network and address are empty, the appender connects to the
+// local syslog daemon via /dev/log.
+func NewAppender(
+	lvl backend.Level,
+	network, address string,
+	layout layout.Factory,
+) (*Appender, error) {
+	switch network {
+	case "":
+		if address != "" {
+			return nil, errors.New("network required if address is set")
+		}
+	case "unixgram", "udp", "udp4", "udp6", "unix", "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("unsupported network '%v'", network)
+	}
+
+	a := &Appender{
+		network: network,
+		address: address,
+	}
+
+	base, err := netappender.New(lvl, layout, (*appenderWriter)(a), a.dial)
+	if err != nil {
+		return nil, err
+	}
+	a.base = base
+	return a, nil
+}
+
+// Close closes the connection. Messages logged after Close are dropped.
+func (a *Appender) Close() error {
+	return a.base.Close()
+}
+
+func (a *Appender) For(name string) backend.Backend {
+	return a
+}
+
+func (a *Appender) IsEnabled(lvl backend.Level) bool {
+	return a.base.IsEnabled(lvl)
+}
+
+func (a *Appender) UseContext() bool {
+	return a.base.UseContext()
+}
+
+func (a *Appender) Log(msg backend.Message) {
+	a.base.Log(msg)
+}
+
+func (a *Appender) dial() (net.Conn, error) {
+	if a.network != "" {
+		conn, err := net.Dial(a.network, a.address)
+		if err != nil {
+			return nil, err
+		}
+		a.stream = isStream(a.network)
+		return conn, nil
+	}
+
+	// Local syslog daemons can listen on datagram or stream sockets.
+	var err error
+	for _, path := range localSockets {
+		for _, network := range []string{"unixgram", "unix"} {
+			var conn net.Conn
+			if conn, err = net.Dial(network, path); err == nil {
+				a.stream = isStream(network)
+				return conn, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("failed to connect to local syslog daemon: %v", err)
+}
+
+func isStream(network string) bool {
+	switch network {
+	case "unix", "tcp", "tcp4", "tcp6":
+		return true
+	default:
+		return false
+	}
+}
+
+func (a *Appender) send(conn net.Conn, msg []byte) (int, error) {
+	if !a.stream {
+		return conn.Write(msg)
+	}
+
+	a.buf.Reset()
+	a.buf.WriteString(strconv.Itoa(len(msg)))
+	a.buf.WriteByte(' ')
+	a.buf.Write(msg)
+	return conn.Write(a.buf.Bytes())
+}
+
+func (w *appenderWriter) Write(msg []byte) (int, error) {
+	a := (*Appender)(w)
+	err := a.base.Send(func(conn net.Conn) (int, error) {
+		return a.send(conn, msg)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(msg), nil
+}
@@ -0,0 +1,172 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package syslog
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/layout"
+)
+
+var testLayout = layout.Syslog(layout.SyslogConfig{
+	Facility: layout.FacilityLocal0,
+	AppName:  "app",
+	Hostname: "test-host",
+})
+
+var rfc5424Pattern = regexp.MustCompile(
+	`^<131>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}\S+ test-host app \d+ test ` +
+		`\[ecslog@32473 custom="a \\"quoted\\" \\] value" host\.hostname="localhost" error\.message="oops"\] ` +
+		`(.*)$`)
+
+func TestUnixgram(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	a, err := NewAppender(backend.Trace, "unixgram", path, testLayout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	a.Log(testMessage("hello"))
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMessage(t, string(buf[:n]), "hello")
+}
+
+func TestTCPReconnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	received := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			// read one message per connection, forcing the appender to reconnect
+			msg, err := readFrame(bufio.NewReader(conn))
+			conn.Close()
+			if err != nil {
+				return
+			}
+			received <- msg
+		}
+	}()
+
+	a, err := NewAppender(backend.Trace, "tcp", l.Addr().String(), testLayout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	a.Log(testMessage("first"))
+	checkMessage(t, receive(t, received), "first")
+
+	// writes into the closed connection can succeed until the peer reset is
+	// noticed. Keep logging until the appender has reconnected.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		a.Log(testMessage("second"))
+		select {
+		case msg := <-received:
+			checkMessage(t, msg, "second")
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("appender did not reconnect")
+		}
+	}
+}
+
+func receive(t *testing.T, ch chan string) string {
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+		return ""
+	}
+}
+
+// readFrame reads an octet-counted frame.
+func readFrame(r *bufio.Reader) (string, error) {
+	length, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(length[:len(length)-1])
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func checkMessage(t *testing.T, msg, expected string) {
+	m := rfc5424Pattern.FindStringSubmatch(msg)
+	if m == nil {
+		t.Fatalf("unexpected message format: %q", msg)
+	}
+	if m[1] != expected {
+		t.Errorf("expected message %q, got %q", expected, m[1])
+	}
+}
+
+func testMessage(msg string) backend.Message {
+	ctx := diag.NewContext(nil, nil)
+	ctx.AddField(diag.String("custom", `a "quoted" ] value`))
+	ctx.AddField(diag.Field{Key: "host.hostname", Value: diag.ValString("localhost"), Standardized: true})
+
+	return backend.Message{
+		Name:    "test",
+		Level:   backend.Error,
+		Caller:  backend.GetCaller(0),
+		Message: msg,
+		Context: ctx,
+		Causes:  []error{errors.New("oops")},
+	}
+}
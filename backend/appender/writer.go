@@ -7,6 +7,7 @@
 package appender
 
 import (
+	"bufio"
 	"io"
 	"os"
 	"sync"
@@ -23,8 +24,17 @@ type writer struct {
 	forceFlush bool
 }
 
+// NewWriter creates a backend writing events to out. Events are delimited by
+// the layout, see layout.Framing. If out is a *bufio.Writer, the buffer is
+// flushed before an event that does not fit, such that events are never split
+// between writes.
 func NewWriter(out io.Writer, lvl backend.Level, layout layout.Factory, forceFlush bool) (backend.Backend, error) {
-	l, err := layout(out)
+	var layoutOut io.Writer = out
+	if buf, ok := out.(*bufio.Writer); ok {
+		layoutOut = alignedWriter{buf}
+	}
+
+	l, err := layout(layoutOut)
 	if err != nil {
 		return nil, err
 	}
@@ -74,3 +84,21 @@ func (w *writer) Log(msg backend.Message) {
 		f.Flush()
 	}
 }
+
+// alignedWriter passes the events written by a layout to a buffered writer.
+// Layouts write each event, including its frame, with a single Write call.
+// The buffer is flushed first if the event does not fit, and events larger
+// than the buffer are written directly, such that events are never split
+// between writes to the underlying writer.
+type alignedWriter struct {
+	buf *bufio.Writer
+}
+
+func (w alignedWriter) Write(b []byte) (int, error) {
+	if len(b) > w.buf.Available() && w.buf.Buffered() > 0 {
+		if err := w.buf.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	return w.buf.Write(b)
+}
@@ -0,0 +1,105 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package appender
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/layout"
+)
+
+// writeRecorder records the individual writes.
+type writeRecorder struct {
+	writes []string
+}
+
+func (r *writeRecorder) Write(b []byte) (int, error) {
+	r.writes = append(r.writes, string(b))
+	return len(b), nil
+}
+
+func testMessages() []backend.Message {
+	var msgs []backend.Message
+	for _, size := range []int{10, 60, 300, 20, 20} {
+		msgs = append(msgs, backend.Message{
+			Level:   backend.Info,
+			Message: strings.Repeat("x", size),
+			Context: diag.NewContext(nil, nil),
+		})
+	}
+	return msgs
+}
+
+func TestNewWriterAligned(t *testing.T) {
+	rec := &writeRecorder{}
+	buf := bufio.NewWriterSize(rec, 256)
+	b, err := NewWriter(buf, backend.Trace, layout.JSON(nil), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := testMessages()
+	for _, msg := range msgs {
+		b.Log(msg)
+	}
+	buf.Flush()
+
+	n := 0
+	for _, w := range rec.writes {
+		if !strings.HasSuffix(w, "\n") {
+			t.Errorf("event split between writes: %q", w)
+		}
+		n += strings.Count(w, "\n")
+	}
+	if n != len(msgs) {
+		t.Errorf("got %v events, want %v", n, len(msgs))
+	}
+}
+
+func TestFileFraming(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.log")
+	b, err := File(backend.Trace, path, 0600, layout.CBOR(nil), 128, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := testMessages()
+	for _, msg := range msgs {
+		b.Log(msg)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r := layout.NewFrameReader(f, layout.FrameCBORSequence, nil)
+	n := 0
+	for {
+		if _, err := r.Next(); err != nil {
+			break
+		}
+		n++
+	}
+	if n != len(msgs) {
+		t.Errorf("got %v events, want %v", n, len(msgs))
+	}
+}
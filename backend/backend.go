@@ -6,7 +6,11 @@
 
 package backend
 
-import "github.com/urso/diag"
+import (
+	"time"
+
+	"github.com/urso/diag"
+)
 
 type Backend interface {
 	For(name string) Backend
@@ -20,12 +24,13 @@ type Backend interface {
 type Level uint8
 
 type Message struct {
-	Name    string
-	Level   Level
-	Caller  Caller
-	Message string
-	Context *diag.Context
-	Causes  []error
+	Name      string
+	Level     Level
+	Timestamp time.Time
+	Caller    Caller
+	Message   string
+	Context   *diag.Context
+	Causes    []error
 }
 
 const (
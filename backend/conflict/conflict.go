@@ -0,0 +1,196 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+// Package conflict provides a backend detecting fields being logged with
+// different types.
+//
+// Elasticsearch determines the type of a field from the first document
+// indexed. Documents with a field of another type are rejected, which is
+// easily missed for user fields logged in different places.
+package conflict
+
+import (
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/internal/flatctx"
+)
+
+// Backend tracks the types of fields logged, before passing messages to the
+// wrapped backend.
+type Backend struct {
+	backend backend.Backend
+	tracker *Tracker
+	config  *config
+}
+
+type config struct {
+	onConflict    func(Conflict)
+	rename        bool
+	userNamespace string
+}
+
+// Option configures the conflict detection backend.
+type Option func(*config) error
+
+// defaultUserNamespace matches the default namespace of user fields in the
+// structured layout.
+const defaultUserNamespace = "fields"
+
+// New creates a backend recording the types of all fields in t. Keys are
+// tracked with the fully qualified name, as reported by the structured
+// layout. Nested contexts are tracked as objects.
+func New(b backend.Backend, t *Tracker, opts ...Option) (*Backend, error) {
+	cfg := &config{userNamespace: defaultUserNamespace}
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+	return &Backend{backend: b, tracker: t, config: cfg}, nil
+}
+
+// OnConflict registers a callback that is run the first time a conflicting
+// type is logged from a call site.
+func OnConflict(fn func(Conflict)) Option {
+	return func(c *config) error {
+		c.onConflict = fn
+		return nil
+	}
+}
+
+// RenameConflicts configures the backend to rename fields with conflicting
+// types before passing the message to the wrapped backend. A suffix based on
+// the type is added to the key, like `status_str` for `status`.
+func RenameConflicts() Option {
+	return func(c *config) error {
+		c.rename = true
+		return nil
+	}
+}
+
+// UserFieldsNamespace configures the namespace user fields are tracked in.
+// It must match the namespace configured in the layout. Defaults to "fields".
+func UserFieldsNamespace(ns string) Option {
+	return func(c *config) error {
+		c.userNamespace = ns
+		return nil
+	}
+}
+
+func (b *Backend) For(name string) backend.Backend {
+	return &Backend{backend: b.backend.For(name), tracker: b.tracker, config: b.config}
+}
+
+func (b *Backend) IsEnabled(lvl backend.Level) bool {
+	return b.backend.IsEnabled(lvl)
+}
+
+func (b *Backend) UseContext() bool {
+	return b.backend.UseContext()
+}
+
+func (b *Backend) Log(msg backend.Message) {
+	if msg.Context != nil && b.backend.UseContext() {
+		msg = b.process(msg)
+	}
+	b.backend.Log(msg)
+}
+
+// trackedField is a field of a message with its fully qualified key. Parent
+// objects are tracked starting at offset, such that the user fields
+// namespace is not tracked.
+type trackedField struct {
+	key    string
+	offset int
+	kind   Kind
+}
+
+func (b *Backend) process(msg backend.Message) backend.Message {
+	userPrefix := ""
+	if b.config.userNamespace != "" {
+		userPrefix = b.config.userNamespace + "."
+	}
+
+	var fields []trackedField
+	collect := func(prefix string) func(string, diag.Value) {
+		return func(key string, v diag.Value) {
+			fields = append(fields, trackedField{key: prefix + key, offset: len(prefix), kind: kindOf(v)})
+		}
+	}
+	flatctx.Visit(msg.Context.Standardized(), "", collect(""))
+	flatctx.Visit(msg.Context.User(), "", collect(userPrefix))
+
+	// Once the fields of a call site have been recorded, most messages do not
+	// introduce new fields or conflicts. Check all fields at once, before
+	// recording the fields one by one.
+	if b.tracker.known(fields) {
+		return msg
+	}
+
+	var renames map[string]string
+	for _, fld := range fields {
+		renamed := b.checkParents(fld.key, fld.offset, msg.Caller)
+		if fld.kind != KindUnknown && b.check(fld.key, fld.kind, msg.Caller) {
+			renamed = renamed + fld.kind.suffix()
+		}
+
+		if renamed != fld.key && b.config.rename {
+			if renames == nil {
+				renames = map[string]string{}
+			}
+			renames[fld.key] = renamed[fld.offset:]
+		}
+	}
+	if len(renames) == 0 {
+		return msg
+	}
+
+	ctx := diag.NewContext(nil, nil)
+	flatctx.Visit(msg.Context.User(), "", func(key string, v diag.Value) {
+		if renamed, exists := renames[userPrefix+key]; exists {
+			key = renamed
+		}
+		ctx.Add(key, v)
+	})
+	flatctx.Visit(msg.Context.Standardized(), "", func(key string, v diag.Value) {
+		if renamed, exists := renames[key]; exists {
+			key = renamed
+		}
+		ctx.AddField(diag.Field{Key: key, Value: v, Standardized: true})
+	})
+
+	msg.Context = ctx
+	return msg
+}
+
+// checkParents tracks the parent objects of a field. The key is returned
+// with the first parent conflicting with a non-object type being renamed.
+// Parents within the user fields namespace (offset) are tracked only.
+func (b *Backend) checkParents(key string, offset int, caller backend.Caller) string {
+	renamed := key
+	for i := offset; i < len(key); i++ {
+		if key[i] != '.' {
+			continue
+		}
+
+		parent := key[:i]
+		if b.check(parent, KindObject, caller) && renamed == key {
+			renamed = parent + KindObject.suffix() + key[i:]
+		}
+	}
+	return renamed
+}
+
+// check records the kind of a field in the tracker. It returns true if the
+// kind conflicts with the kind seen first.
+func (b *Backend) check(key string, kind Kind, caller backend.Caller) bool {
+	c, isConflict, newSite := b.tracker.check(key, kind, caller)
+	if newSite && b.config.onConflict != nil {
+		b.config.onConflict(c)
+	}
+	return isConflict
+}
@@ -0,0 +1,264 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package conflict
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/internal/flatctx"
+)
+
+type recordBackend struct {
+	messages []backend.Message
+}
+
+func (b *recordBackend) For(_ string) backend.Backend   { return b }
+func (b *recordBackend) IsEnabled(_ backend.Level) bool { return true }
+func (b *recordBackend) UseContext() bool               { return true }
+func (b *recordBackend) Log(msg backend.Message)        { b.messages = append(b.messages, msg) }
+func (b *recordBackend) last() backend.Message          { return b.messages[len(b.messages)-1] }
+
+func newTestBackend(t *testing.T, opts ...Option) (*Backend, *Tracker, *recordBackend) {
+	t.Helper()
+
+	tracker := NewTracker()
+	rec := &recordBackend{}
+	b, err := New(rec, tracker, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b, tracker, rec
+}
+
+func testMessage(caller backend.Caller, fields ...diag.Field) backend.Message {
+	ctx := diag.NewContext(nil, nil)
+	ctx.AddFields(fields...)
+	return backend.Message{Level: backend.Info, Message: "test", Context: ctx, Caller: caller}
+}
+
+// contextKeys returns the fully qualified keys of all fields in ctx. Keys of
+// standardized fields are prefixed with `std:`.
+func contextKeys(ctx *diag.Context) map[string]bool {
+	keys := map[string]bool{}
+	flatctx.Visit(ctx.Standardized(), "", func(key string, _ diag.Value) { keys["std:"+key] = true })
+	flatctx.Visit(ctx.User(), "", func(key string, _ diag.Value) { keys[key] = true })
+	return keys
+}
+
+func TestFirstSeenType(t *testing.T) {
+	var conflicts []Conflict
+	b, tracker, _ := newTestBackend(t, OnConflict(func(c Conflict) {
+		conflicts = append(conflicts, c)
+	}))
+
+	first := backend.GetCaller(0)
+	b.Log(testMessage(first, diag.Int("status", 200), diag.String("name", "a")))
+	b.Log(testMessage(first, diag.Int("status", 404), diag.String("name", "b")))
+	if len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %v", conflicts)
+	}
+
+	second := backend.GetCaller(0)
+	b.Log(testMessage(second, diag.String("status", "ok")))
+	if len(conflicts) != 1 {
+		t.Fatalf("expected one conflict, got %v", conflicts)
+	}
+
+	c := conflicts[0]
+	if c.Key != "fields.status" || c.Kind != KindString || c.Expected != KindLong {
+		t.Errorf("unexpected conflict: %v", c)
+	}
+	if c.Caller.PC != second.PC || c.FirstCaller.PC != first.PC {
+		t.Errorf("unexpected call sites in conflict: %v", c)
+	}
+
+	// the type seen first is kept
+	b.Log(testMessage(second, diag.Int("status", 200)))
+	r := tracker.Report(true)
+	if len(r.Fields) != 2 || r.Fields[1].Key != "fields.status" || r.Fields[1].Type != "long" {
+		t.Errorf("unexpected report: %+v", r)
+	}
+}
+
+func TestConflictPerCallSite(t *testing.T) {
+	var conflicts []Conflict
+	b, tracker, _ := newTestBackend(t, OnConflict(func(c Conflict) {
+		conflicts = append(conflicts, c)
+	}))
+
+	b.Log(testMessage(backend.GetCaller(0), diag.Int("status", 200)))
+
+	site1 := backend.GetCaller(0)
+	site2 := backend.GetCaller(0)
+	for i := 0; i < 3; i++ {
+		b.Log(testMessage(site1, diag.String("status", "ok")))
+		b.Log(testMessage(site2, diag.String("status", "ok")))
+	}
+	b.Log(testMessage(site1, diag.Bool("status", true)))
+
+	if len(conflicts) != 3 {
+		t.Fatalf("expected a conflict per call site and type, got %v", conflicts)
+	}
+
+	r := tracker.Report(false)
+	if len(r.Fields) != 1 {
+		t.Fatalf("expected one field in report, got %+v", r)
+	}
+	cs := r.Fields[0].Conflicts
+	if len(cs) != 2 || cs[0].Type != "boolean" || cs[0].Count != 1 || cs[1].Type != "string" || cs[1].Count != 6 {
+		t.Fatalf("unexpected conflicts in report: %+v", cs)
+	}
+	if len(cs[1].Sites) != 2 {
+		t.Errorf("expected 2 sites, got %+v", cs[1].Sites)
+	}
+}
+
+func TestRenameConflicts(t *testing.T) {
+	cases := map[string]struct {
+		first  []diag.Field
+		fields []diag.Field
+		want   []string
+	}{
+		"no conflict": {
+			first:  []diag.Field{diag.Int("status", 200)},
+			fields: []diag.Field{diag.Int("status", 404)},
+			want:   []string{"status"},
+		},
+		"user field": {
+			first:  []diag.Field{diag.Int("status", 200)},
+			fields: []diag.Field{diag.String("status", "ok"), diag.String("other", "x")},
+			want:   []string{"status_str", "other"},
+		},
+		"standardized field": {
+			first:  []diag.Field{{Key: "http.response.status_code", Value: diag.ValInt(200), Standardized: true}},
+			fields: []diag.Field{{Key: "http.response.status_code", Value: diag.ValString("ok"), Standardized: true}},
+			want:   []string{"std:http.response.status_code_str"},
+		},
+		"value in object": {
+			first:  []diag.Field{diag.String("req.id", "x")},
+			fields: []diag.Field{diag.String("req", "y")},
+			want:   []string{"req_str"},
+		},
+		"parent object": {
+			first:  []diag.Field{diag.Int("req", 1)},
+			fields: []diag.Field{diag.String("req.id", "x"), diag.String("req.name", "y")},
+			want:   []string{"req_obj.id", "req_obj.name"},
+		},
+		"nested parent object": {
+			first:  []diag.Field{diag.Int("a.b", 1)},
+			fields: []diag.Field{diag.Int("a.b.c", 2)},
+			want:   []string{"a.b_obj.c"},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			b, _, rec := newTestBackend(t, RenameConflicts())
+			caller := backend.GetCaller(0)
+			b.Log(testMessage(caller, test.first...))
+			b.Log(testMessage(caller, test.fields...))
+
+			keys := contextKeys(rec.last().Context)
+			if len(keys) != len(test.want) {
+				t.Fatalf("expected keys %v, got %v", test.want, keys)
+			}
+			for _, key := range test.want {
+				if !keys[key] {
+					t.Errorf("missing key %v in %v", key, keys)
+				}
+			}
+		})
+	}
+}
+
+func TestUserFieldsNamespace(t *testing.T) {
+	b, tracker, _ := newTestBackend(t, UserFieldsNamespace(""))
+	b.Log(testMessage(backend.GetCaller(0),
+		diag.Int("status", 200),
+		diag.Field{Key: "host.hostname", Value: diag.ValString("h1"), Standardized: true},
+	))
+
+	r := tracker.Report(true)
+	var keys []string
+	for _, fld := range r.Fields {
+		keys = append(keys, fld.Key)
+	}
+	if len(keys) != 3 || keys[0] != "host" || keys[1] != "host.hostname" || keys[2] != "status" {
+		t.Errorf("unexpected fields: %v", keys)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	b, tracker, _ := newTestBackend(t)
+	caller := backend.GetCaller(0)
+	b.Log(testMessage(caller, diag.Int("status", 200), diag.String("name", "a")))
+	b.Log(testMessage(caller, diag.String("status", "ok")))
+
+	cases := map[string]struct {
+		url  string
+		keys []string
+	}{
+		"conflicts": {url: "/", keys: []string{"fields.status"}},
+		"all":       {url: "/?all", keys: []string{"fields.name", "fields.status"}},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tracker.ServeHTTP(w, httptest.NewRequest("GET", test.url, nil))
+
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("unexpected content type %v", ct)
+			}
+
+			var report struct {
+				Fields []struct {
+					Key       string `json:"key"`
+					Type      string `json:"type"`
+					Site      Site   `json:"site"`
+					Conflicts []struct {
+						Type  string `json:"type"`
+						Count uint64 `json:"count"`
+						Sites []struct {
+							File     string `json:"file"`
+							Line     int    `json:"line"`
+							Function string `json:"function"`
+						} `json:"sites"`
+					} `json:"conflicts"`
+				} `json:"fields"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+			}
+
+			if len(report.Fields) != len(test.keys) {
+				t.Fatalf("expected fields %v, got %v", test.keys, w.Body.String())
+			}
+			for i, key := range test.keys {
+				if report.Fields[i].Key != key {
+					t.Errorf("expected field %v, got %v", key, report.Fields[i].Key)
+				}
+			}
+
+			status := report.Fields[len(report.Fields)-1]
+			if status.Type != "long" || len(status.Conflicts) != 1 {
+				t.Fatalf("unexpected field report: %v", w.Body.String())
+			}
+			c := status.Conflicts[0]
+			if c.Type != "string" || c.Count != 1 || len(c.Sites) != 1 || c.Sites[0].Function != "github.com/urso/ecslog/backend/conflict.TestServeHTTP" {
+				t.Errorf("unexpected conflict report: %v", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package conflict
+
+import (
+	"net"
+	"reflect"
+	"time"
+
+	"github.com/urso/diag"
+)
+
+// Kind is the type a field is indexed as by Elasticsearch dynamic mappings.
+type Kind uint8
+
+const (
+	KindUnknown Kind = iota
+	KindString
+	KindLong
+	KindDouble
+	KindBool
+	KindDate
+	KindObject
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindLong:
+		return "long"
+	case KindDouble:
+		return "double"
+	case KindBool:
+		return "boolean"
+	case KindDate:
+		return "date"
+	case KindObject:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// suffix is appended to the key of conflicting fields, if fields are
+// renamed.
+func (k Kind) suffix() string {
+	switch k {
+	case KindString:
+		return "_str"
+	case KindLong:
+		return "_long"
+	case KindDouble:
+		return "_double"
+	case KindBool:
+		return "_bool"
+	case KindDate:
+		return "_date"
+	case KindObject:
+		return "_obj"
+	default:
+		return "_unknown"
+	}
+}
+
+// kindOf determines the Kind of a value. KindUnknown is returned for nil and
+// empty arrays, which do not influence the mapping.
+func kindOf(v diag.Value) Kind {
+	switch v.Reporter.Type() {
+	case diag.BoolType:
+		return KindBool
+	case diag.IntType, diag.Int64Type, diag.Uint64Type, diag.DurationType:
+		return KindLong
+	case diag.Float64Type:
+		return KindDouble
+	case diag.TimestampType:
+		return KindDate
+	case diag.StringType:
+		return KindString
+	default:
+		return kindOfIfc(v.Interface())
+	}
+}
+
+func kindOfIfc(ifc interface{}) Kind {
+	switch ifc.(type) {
+	case nil:
+		return KindUnknown
+	case time.Time:
+		return KindDate
+	case time.Duration:
+		return KindLong
+	case net.IP, []byte:
+		return KindString
+	}
+
+	rv := reflect.ValueOf(ifc)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return KindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return KindLong
+	case reflect.Float32, reflect.Float64:
+		return KindDouble
+	case reflect.String:
+		return KindString
+	case reflect.Map, reflect.Struct:
+		return KindObject
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if k := kindOfIfc(rv.Index(i).Interface()); k != KindUnknown {
+				return k
+			}
+		}
+		return KindUnknown
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return KindUnknown
+		}
+		return kindOfIfc(rv.Elem().Interface())
+	default:
+		return KindUnknown
+	}
+}
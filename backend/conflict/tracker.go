@@ -0,0 +1,207 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package conflict
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/elastic/go-structform/gotype"
+	"github.com/elastic/go-structform/json"
+
+	"github.com/urso/ecslog/backend"
+)
+
+// Tracker records the first seen type of each field. A Tracker is shared by
+// all backends logging into the same index, usually one per process.
+type Tracker struct {
+	mu     sync.RWMutex
+	fields map[string]*fieldState
+}
+
+type fieldState struct {
+	kind      Kind
+	caller    backend.Caller
+	conflicts map[Kind]*conflictState
+}
+
+type conflictState struct {
+	count   uint64
+	callers map[uintptr]backend.Caller
+}
+
+// Conflict reports a field being logged with another type than the type it
+// has been seen with first.
+type Conflict struct {
+	Key      string
+	Kind     Kind
+	Expected Kind
+
+	Caller      backend.Caller
+	FirstCaller backend.Caller
+}
+
+// Report lists the fields seen by a Tracker.
+type Report struct {
+	Fields []FieldReport `struct:"fields"`
+}
+
+// FieldReport describes the first seen type of a field and all conflicting
+// types logged.
+type FieldReport struct {
+	Key       string           `struct:"key"`
+	Type      string           `struct:"type"`
+	Site      Site             `struct:"site"`
+	Conflicts []ConflictReport `struct:"conflicts,omitempty"`
+}
+
+// ConflictReport lists all call sites a conflicting type has been logged at.
+type ConflictReport struct {
+	Type  string `struct:"type"`
+	Count uint64 `struct:"count"`
+	Sites []Site `struct:"sites"`
+}
+
+// Site is the source code location a field has been logged at.
+type Site struct {
+	File     string `struct:"file"`
+	Line     int    `struct:"line"`
+	Function string `struct:"function"`
+}
+
+// NewTracker creates an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{fields: map[string]*fieldState{}}
+}
+
+// known checks if all fields and their parent objects have been recorded
+// with the same kind before, such that checking the fields would neither
+// record new fields nor report conflicts.
+func (t *Tracker) known(fields []trackedField) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, fld := range fields {
+		for i := fld.offset; i < len(fld.key); i++ {
+			if fld.key[i] == '.' && !t.hasKind(fld.key[:i], KindObject) {
+				return false
+			}
+		}
+		if fld.kind != KindUnknown && !t.hasKind(fld.key, fld.kind) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasKind checks if the field has been seen with kind first. The read lock
+// must be held.
+func (t *Tracker) hasKind(key string, kind Kind) bool {
+	st := t.fields[key]
+	return st != nil && st.kind == kind
+}
+
+// check records the kind of a field. Conflicts are returned if the field has
+// been seen with another kind before. The conflict is only reported once per
+// call site.
+func (t *Tracker) check(key string, kind Kind, caller backend.Caller) (Conflict, bool, bool) {
+	t.mu.RLock()
+	st := t.fields[key]
+	if st != nil && st.kind == kind {
+		t.mu.RUnlock()
+		return Conflict{}, false, false
+	}
+	t.mu.RUnlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st = t.fields[key]
+	if st == nil {
+		t.fields[key] = &fieldState{kind: kind, caller: caller}
+		return Conflict{}, false, false
+	}
+	if st.kind == kind {
+		return Conflict{}, false, false
+	}
+
+	if st.conflicts == nil {
+		st.conflicts = map[Kind]*conflictState{}
+	}
+	cs := st.conflicts[kind]
+	if cs == nil {
+		cs = &conflictState{callers: map[uintptr]backend.Caller{}}
+		st.conflicts[kind] = cs
+	}
+	cs.count++
+
+	_, known := cs.callers[caller.PC]
+	if !known {
+		cs.callers[caller.PC] = caller
+	}
+
+	return Conflict{
+		Key:         key,
+		Kind:        kind,
+		Expected:    st.kind,
+		Caller:      caller,
+		FirstCaller: st.caller,
+	}, true, !known
+}
+
+// Report creates a report of all fields with conflicting types, sorted by
+// key. All fields seen are reported if all is set.
+func (t *Tracker) Report(all bool) Report {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var r Report
+	for key, st := range t.fields {
+		if !all && len(st.conflicts) == 0 {
+			continue
+		}
+
+		fr := FieldReport{Key: key, Type: st.kind.String(), Site: siteOf(st.caller)}
+		for kind, cs := range st.conflicts {
+			cr := ConflictReport{Type: kind.String(), Count: cs.count}
+			for _, caller := range cs.callers {
+				cr.Sites = append(cr.Sites, siteOf(caller))
+			}
+			sort.Slice(cr.Sites, func(i, j int) bool {
+				a, b := cr.Sites[i], cr.Sites[j]
+				return a.File < b.File || (a.File == b.File && a.Line < b.Line)
+			})
+			fr.Conflicts = append(fr.Conflicts, cr)
+		}
+		sort.Slice(fr.Conflicts, func(i, j int) bool {
+			return fr.Conflicts[i].Type < fr.Conflicts[j].Type
+		})
+		r.Fields = append(r.Fields, fr)
+	}
+
+	sort.Slice(r.Fields, func(i, j int) bool {
+		return r.Fields[i].Key < r.Fields[j].Key
+	})
+	return r
+}
+
+// ServeHTTP writes the report as JSON document. Only fields with conflicts
+// are reported, unless the query parameter `all` is set.
+func (t *Tracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	_, all := req.URL.Query()["all"]
+	report := t.Report(all)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := gotype.Fold(report, json.NewVisitor(w)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func siteOf(caller backend.Caller) Site {
+	return Site{File: caller.File(), Line: caller.Line(), Function: caller.Function()}
+}
@@ -0,0 +1,88 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+// Package filter provides a backend removing context fields from log
+// messages, before passing the messages to the wrapped backend.
+//
+// Use the filter backend to apply the same filter to all outputs of a
+// backend. Filters specific to a single output are configured via the
+// layout.FilterFields layout option.
+package filter
+
+import (
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/layout"
+)
+
+// Backend filters the context of log messages, before passing the messages
+// to the wrapped backend.
+type Backend struct {
+	backend backend.Backend
+	filter  func(*diag.Context) *diag.Context
+}
+
+type config struct {
+	userNamespace string
+}
+
+// Option configures the filtering backend.
+type Option func(*config) error
+
+// defaultUserNamespace matches the default namespace of user fields in the
+// structured layout.
+const defaultUserNamespace = "fields"
+
+// New creates a backend reporting only the context fields selected by f.
+// Patterns are matched against the keys as reported by the structured
+// layout (see layout.FieldFilter).
+//
+// The backend filters the context of the log message only. Error values are
+// passed as is, such that the contexts of errors are filtered by layouts
+// configured via layout.FilterFields only.
+func New(b backend.Backend, f layout.FieldFilter, opts ...Option) (*Backend, error) {
+	cfg := &config{userNamespace: defaultUserNamespace}
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	filter, err := layout.ContextFilter(f, cfg.userNamespace)
+	if err != nil {
+		return nil, err
+	}
+	return &Backend{backend: b, filter: filter}, nil
+}
+
+// UserFieldsNamespace configures the namespace user fields are matched in.
+// It must match the namespace configured in the layout. Defaults to "fields".
+func UserFieldsNamespace(ns string) Option {
+	return func(c *config) error {
+		c.userNamespace = ns
+		return nil
+	}
+}
+
+func (b *Backend) For(name string) backend.Backend {
+	return &Backend{backend: b.backend.For(name), filter: b.filter}
+}
+
+func (b *Backend) IsEnabled(lvl backend.Level) bool {
+	return b.backend.IsEnabled(lvl)
+}
+
+func (b *Backend) UseContext() bool {
+	return b.backend.UseContext()
+}
+
+func (b *Backend) Log(msg backend.Message) {
+	if msg.Context != nil && b.backend.UseContext() {
+		msg.Context = b.filter(msg.Context)
+	}
+	b.backend.Log(msg)
+}
@@ -0,0 +1,93 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/layout"
+)
+
+type recordBackend struct {
+	messages []backend.Message
+}
+
+func (b *recordBackend) For(_ string) backend.Backend   { return b }
+func (b *recordBackend) IsEnabled(_ backend.Level) bool { return true }
+func (b *recordBackend) UseContext() bool               { return true }
+func (b *recordBackend) Log(msg backend.Message)        { b.messages = append(b.messages, msg) }
+func (b *recordBackend) last() backend.Message          { return b.messages[len(b.messages)-1] }
+
+func TestFilter(t *testing.T) {
+	cases := map[string]struct {
+		filter layout.FieldFilter
+		opts   []Option
+		keys   []string
+	}{
+		"no patterns": {
+			keys: []string{"id", "secret", "host.hostname"},
+		},
+		"exclude user fields": {
+			filter: layout.FieldFilter{Exclude: []string{"fields"}},
+			keys:   []string{"host.hostname"},
+		},
+		"include": {
+			filter: layout.FieldFilter{Include: []string{"host", "fields.id"}},
+			keys:   []string{"id", "host.hostname"},
+		},
+		"root namespace": {
+			filter: layout.FieldFilter{Exclude: []string{"secret"}},
+			opts:   []Option{UserFieldsNamespace("")},
+			keys:   []string{"id", "host.hostname"},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			rec := &recordBackend{}
+			b, err := New(rec, test.filter, test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := diag.NewContext(nil, nil)
+			ctx.AddAll("id", 42, "secret", "x")
+			ctx.AddField(diag.Field{Key: "host.hostname", Value: diag.ValString("h1"), Standardized: true})
+			b.For("test").Log(backend.Message{Message: "hello", Context: ctx})
+
+			keys := map[string]bool{}
+			rec.last().Context.VisitKeyValues(visitKeys(keys))
+			if len(keys) != len(test.keys) {
+				t.Fatalf("expected keys %v, got %v", test.keys, keys)
+			}
+			for _, key := range test.keys {
+				if !keys[key] {
+					t.Errorf("missing key %v in %v", key, keys)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidPattern(t *testing.T) {
+	if _, err := New(&recordBackend{}, layout.FieldFilter{Exclude: []string{"[a"}}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+type visitKeys map[string]bool
+
+func (v visitKeys) OnObjStart(_ string) error { return nil }
+func (v visitKeys) OnObjEnd() error           { return nil }
+func (v visitKeys) OnValue(key string, _ diag.Value) error {
+	v[key] = true
+	return nil
+}
@@ -0,0 +1,35 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+// Package flatctx reports the fields of a diag.Context with fully qualified
+// keys, as used by the backends checking fields against the document
+// written by the structured layout.
+package flatctx
+
+import "github.com/urso/diag"
+
+// Values adapts a function to the diag.Visitor interface. Object boundaries
+// are ignored.
+type Values func(key string, v diag.Value)
+
+func (fn Values) OnObjStart(_ string) error { return nil }
+func (fn Values) OnObjEnd() error           { return nil }
+func (fn Values) OnValue(key string, v diag.Value) error {
+	fn(key, v)
+	return nil
+}
+
+// Visit reports all values in ctx with their fully qualified key. Nested
+// contexts are flattened into dotted keys.
+func Visit(ctx *diag.Context, prefix string, fn func(key string, v diag.Value)) {
+	ctx.VisitKeyValues(Values(func(key string, v diag.Value) {
+		if nested, ok := v.Interface().(*diag.Context); ok {
+			Visit(nested, prefix+key+".", fn)
+		} else {
+			fn(prefix+key, v)
+		}
+	}))
+}
@@ -0,0 +1,231 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/urso/diag"
+	"github.com/urso/ecslog/backend"
+)
+
+// BulkConfig configures the index and data stream the documents written by
+// the Bulk layout are created in.
+type BulkConfig struct {
+	// Index is the template of the index or data stream name. The template
+	// supports the placeholders {type}, {dataset}, {namespace}, {logger}, and
+	// {+layout}. The latter formats the event time in UTC with the Go time
+	// layout, like {+2006.01.02} for daily indices.
+	// Defaults to `logs-{dataset}-{namespace}`.
+	Index string
+
+	// Dataset is reported in `data_stream.dataset`. Defaults to the logger
+	// name, or `generic` if the logger has no name.
+	Dataset string
+
+	// Namespace is reported in `data_stream.namespace`. Defaults to `default`.
+	Namespace string
+}
+
+const (
+	defaultBulkIndex     = "logs-{dataset}-{namespace}"
+	defaultBulkDataset   = "generic"
+	defaultBulkNamespace = "default"
+
+	// dataStreamType is the data stream type of log events.
+	dataStreamType = "logs"
+
+	// maxDataStreamPart is the maximum length of the dataset and namespace.
+	maxDataStreamPart = 100
+)
+
+// invalidIndexChars are not allowed in index names.
+const invalidIndexChars = `\/*?"<>| ,#:{}`
+
+// bulkIndex creates the action line and the data_stream fields of an event.
+type bulkIndex struct {
+	parts     []indexPart
+	dataset   string
+	namespace string
+}
+
+type indexPart struct {
+	kind indexPartKind
+	text string // literal text or time layout
+}
+
+type indexPartKind uint8
+
+const (
+	indexLiteral indexPartKind = iota
+	indexType
+	indexDataset
+	indexNamespace
+	indexLogger
+	indexTime
+)
+
+// Bulk creates an ECS layout writing events in the NDJSON format of the
+// Elasticsearch `_bulk` API. Each document is preceded by a `create` action
+// line, such that files written by the layout can be POSTed to `_bulk` as is.
+// The documents include the `data_stream.type`, `data_stream.dataset`, and
+// `data_stream.namespace` fields. Events are always framed by newlines.
+func Bulk(cfg BulkConfig, fields []diag.Field, opts ...Option) Factory {
+	opts = append(opts, bulkAction(cfg), Framed(FrameNewline))
+	return ECS(fields, opts...)
+}
+
+func bulkAction(cfg BulkConfig) Option {
+	return func(o *options) error {
+		idx, err := newBulkIndex(cfg)
+		if err != nil {
+			return err
+		}
+		o.bulk = idx
+		return nil
+	}
+}
+
+func newBulkIndex(cfg BulkConfig) (*bulkIndex, error) {
+	tmpl := cfg.Index
+	if tmpl == "" {
+		tmpl = defaultBulkIndex
+	}
+	parts, err := parseIndexTemplate(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &bulkIndex{parts: parts, dataset: cfg.Dataset, namespace: cfg.Namespace}
+	if idx.dataset != "" {
+		idx.dataset = dataStreamPart(idx.dataset)
+	}
+	if idx.namespace == "" {
+		idx.namespace = defaultBulkNamespace
+	}
+	idx.namespace = dataStreamPart(idx.namespace)
+	return idx, nil
+}
+
+func parseIndexTemplate(tmpl string) ([]indexPart, error) {
+	var parts []indexPart
+	for rest := tmpl; rest != ""; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			start = len(rest)
+		}
+		if start > 0 {
+			lit := rest[:start]
+			if strings.ContainsAny(lit, invalidIndexChars) || needsEscape(lit) || lit != strings.ToLower(lit) {
+				return nil, fmt.Errorf("invalid index name '%v' in template '%v'", lit, tmpl)
+			}
+			parts = append(parts, indexPart{kind: indexLiteral, text: lit})
+			rest = rest[start:]
+			continue
+		}
+
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return nil, fmt.Errorf("missing '}' in index template '%v'", tmpl)
+		}
+
+		name := rest[1:end]
+		rest = rest[end+1:]
+		switch {
+		case name == "type":
+			parts = append(parts, indexPart{kind: indexType})
+		case name == "dataset":
+			parts = append(parts, indexPart{kind: indexDataset})
+		case name == "namespace":
+			parts = append(parts, indexPart{kind: indexNamespace})
+		case name == "logger":
+			parts = append(parts, indexPart{kind: indexLogger})
+		case len(name) > 1 && name[0] == '+':
+			parts = append(parts, indexPart{kind: indexTime, text: name[1:]})
+		default:
+			return nil, fmt.Errorf("unknown placeholder '{%v}' in index template '%v'", name, tmpl)
+		}
+	}
+
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("empty index template")
+	}
+	return parts, nil
+}
+
+// datasetOf returns the dataset of the event.
+func (idx *bulkIndex) datasetOf(msg backend.Message) string {
+	switch {
+	case idx.dataset != "":
+		return idx.dataset
+	case msg.Name != "":
+		return dataStreamPart(msg.Name)
+	default:
+		return defaultBulkDataset
+	}
+}
+
+// fields returns the data_stream fields of the event.
+func (idx *bulkIndex) fields(msg backend.Message) []diag.Field {
+	return []diag.Field{
+		{Key: "data_stream.type", Value: diag.ValString(dataStreamType), Standardized: true},
+		{Key: "data_stream.dataset", Value: diag.ValString(idx.datasetOf(msg)), Standardized: true},
+		{Key: "data_stream.namespace", Value: diag.ValString(idx.namespace), Standardized: true},
+	}
+}
+
+// appendAction appends the `create` action line of the event to dst.
+// Placeholder values are sanitized and literals are validated when parsing
+// the template, such that the index name does not require JSON escaping.
+func (idx *bulkIndex) appendAction(dst []byte, msg backend.Message) []byte {
+	dst = append(dst, `{"create":{"_index":"`...)
+	for _, part := range idx.parts {
+		switch part.kind {
+		case indexLiteral:
+			dst = append(dst, part.text...)
+		case indexType:
+			dst = append(dst, dataStreamType...)
+		case indexDataset:
+			dst = append(dst, idx.datasetOf(msg)...)
+		case indexNamespace:
+			dst = append(dst, idx.namespace...)
+		case indexLogger:
+			dst = append(dst, sanitizeIndexName(msg.Name, false)...)
+		case indexTime:
+			ts := eventTime(msg).UTC().Format(part.text)
+			dst = append(dst, sanitizeIndexName(ts, true)...)
+		}
+	}
+	return append(dst, "\"}}\n"...)
+}
+
+// dataStreamPart sanitizes a dataset or namespace, and limits its length.
+func dataStreamPart(s string) string {
+	s = sanitizeIndexName(s, false)
+	if len(s) <= maxDataStreamPart {
+		return s
+	}
+	n := maxDataStreamPart
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// sanitizeIndexName lower cases s and replaces characters not allowed in
+// index names with '_'. Data stream names use '-' to separate type, dataset,
+// and namespace, such that '-' is replaced as well, unless keepDash is set.
+func sanitizeIndexName(s string, keepDash bool) string {
+	return strings.Map(func(r rune) rune {
+		if (r == '-' && !keepDash) || strings.ContainsRune(invalidIndexChars, r) || isControl(r) {
+			return '_'
+		}
+		return r
+	}, strings.ToLower(s))
+}
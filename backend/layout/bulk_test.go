@@ -0,0 +1,87 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBulk(t *testing.T) {
+	ts := time.Date(2020, 3, 14, 23, 59, 0, 0, time.FixedZone("", -3600))
+
+	cases := []struct {
+		name    string
+		cfg     BulkConfig
+		logger  string
+		index   string
+		dataset string
+	}{
+		{"defaults", BulkConfig{}, "My-App", "logs-my_app-default", "my_app"},
+		{"no logger", BulkConfig{}, "", "logs-generic-default", "generic"},
+		{"configured", BulkConfig{Dataset: "nginx.access", Namespace: "Prod"}, "test", "logs-nginx.access-prod", "nginx.access"},
+		{"daily index", BulkConfig{Index: "app-{logger}-{+2006-01-02}"}, "http", "app-http-2020-03-15", "http"},
+	}
+
+	for _, test := range cases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l, err := Bulk(test.cfg, nil)(&out)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			msg := testFrameMessage("hello")
+			msg.Name, msg.Timestamp = test.logger, ts
+			l.Log(msg)
+
+			lines := bytes.Split(bytes.TrimSuffix(out.Bytes(), []byte("\n")), []byte("\n"))
+			if len(lines) != 2 {
+				t.Fatalf("expected action and document lines, got: %q", out.String())
+			}
+
+			var action struct {
+				Create struct {
+					Index string `json:"_index"`
+				} `json:"create"`
+			}
+			if err := json.Unmarshal(lines[0], &action); err != nil {
+				t.Fatalf("invalid action %q: %v", lines[0], err)
+			}
+			if action.Create.Index != test.index {
+				t.Errorf("index: got %q, want %q", action.Create.Index, test.index)
+			}
+
+			var doc struct {
+				Timestamp  string `json:"@timestamp"`
+				DataStream struct {
+					Type, Dataset, Namespace string
+				} `json:"data_stream"`
+			}
+			if err := json.Unmarshal(lines[1], &doc); err != nil {
+				t.Fatalf("invalid document %q: %v", lines[1], err)
+			}
+			if doc.Timestamp == "" {
+				t.Error("missing @timestamp")
+			}
+			if doc.DataStream.Type != "logs" || doc.DataStream.Dataset != test.dataset {
+				t.Errorf("unexpected data_stream fields: %+v", doc.DataStream)
+			}
+		})
+	}
+}
+
+func TestBulkInvalidIndex(t *testing.T) {
+	for _, index := range []string{"Logs-{dataset}", "logs-{unknown}", "logs-{dataset", "logs/{dataset}"} {
+		if _, err := Bulk(BulkConfig{Index: index}, nil)(&bytes.Buffer{}); err == nil {
+			t.Errorf("expected error for index template %q", index)
+		}
+	}
+}
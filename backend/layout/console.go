@@ -0,0 +1,105 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/urso/ecslog/backend"
+)
+
+// ColorScheme configures the colors used by the text layout. Each color is
+// given as the parameter list of an ANSI SGR escape sequence, like "1;31" for
+// bold red. Empty strings disable coloring for the respective element.
+type ColorScheme struct {
+	// Level badge colors.
+	Trace, Debug, Info, Error string
+
+	Timestamp string
+	Caller    string
+	Key       string // context field keys
+	Cause     string // error messages of the causes
+}
+
+// DefaultColorScheme is used by the Console layout.
+var DefaultColorScheme = ColorScheme{
+	Trace:     "90",
+	Debug:     "36",
+	Info:      "1;32",
+	Error:     "1;31",
+	Timestamp: "2",
+	Caller:    "2",
+	Key:       "34",
+	Cause:     "31",
+}
+
+// Console creates a colorized text layout for developer consoles. Colors are
+// only used if the output is a terminal. See Colors for details.
+func Console(withCtx bool, opts ...Option) Factory {
+	return Text(withCtx, append([]Option{Colors(DefaultColorScheme)}, opts...)...)
+}
+
+// Colors configures the text layout to use ANSI colors. Colors are only
+// written if the output is a terminal, or if the FORCE_COLOR environment
+// variable is set. Colors are always disabled if NO_COLOR is set.
+func Colors(scheme ColorScheme) Option {
+	return func(o *options) error {
+		o.colors = &scheme
+		return nil
+	}
+}
+
+func useColors(out io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("FORCE_COLOR"); force != "" && force != "0" && force != "false" {
+		return true
+	}
+	return isTerminal(out)
+}
+
+// isTerminal checks if out is a terminal. Other character devices, like
+// /dev/null, are not considered to be terminals.
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+func (c *ColorScheme) level(lvl backend.Level) string {
+	switch lvl {
+	case backend.Trace:
+		return c.Trace
+	case backend.Debug:
+		return c.Debug
+	case backend.Info:
+		return c.Info
+	case backend.Error:
+		return c.Error
+	default:
+		return ""
+	}
+}
+
+// writeColored writes s, surrounded by the escape sequences for color. No
+// escape sequences are written if color is empty.
+func writeColored(buf *bytes.Buffer, color, s string) {
+	if color == "" {
+		buf.WriteString(s)
+		return
+	}
+
+	buf.WriteString("\x1b[")
+	buf.WriteString(color)
+	buf.WriteByte('m')
+	buf.WriteString(s)
+	buf.WriteString("\x1b[0m")
+}
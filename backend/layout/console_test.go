@@ -0,0 +1,114 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/urso/ecslog/backend"
+)
+
+func TestUseColors(t *testing.T) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	cases := map[string]struct {
+		noColor, forceColor string
+		out                 *os.File
+		want                bool
+	}{
+		"no terminal":          {want: false},
+		"char device":          {out: devNull, want: false},
+		"force":                {forceColor: "1", want: true},
+		"force disabled by 0":  {forceColor: "0", want: false},
+		"force disabled":       {forceColor: "false", want: false},
+		"no color":             {noColor: "1", want: false},
+		"no color beats force": {noColor: "1", forceColor: "1", want: false},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", test.noColor)
+			t.Setenv("FORCE_COLOR", test.forceColor)
+
+			var out io.Writer = &bytes.Buffer{}
+			if test.out != nil {
+				out = test.out
+			}
+			if got := useColors(out); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConsoleColors(t *testing.T) {
+	msg := testMessage("hello", "id", 42)
+	msg.Causes = []error{errors.New("failed")}
+
+	cases := map[string]struct {
+		level   backend.Level
+		opts    []Option
+		want    []string
+		exclude []string
+	}{
+		"info": {
+			level: backend.Info,
+			want:  []string{"\x1b[1;32mINFO\x1b[0m", "\x1b[34mid\x1b[0m", "\x1b[31mfailed\x1b[0m"},
+		},
+		"error": {
+			level: backend.Error,
+			want:  []string{"\x1b[1;31mERROR\x1b[0m"},
+		},
+		"custom scheme": {
+			level:   backend.Info,
+			opts:    []Option{Colors(ColorScheme{Info: "35"})},
+			want:    []string{"\x1b[35mINFO\x1b[0m", "\tfailed\n"},
+			exclude: []string{"\x1b[34m", "\x1b[31m"},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "")
+			t.Setenv("FORCE_COLOR", "1")
+
+			msg := msg
+			msg.Level = test.level
+			out := logString(t, Console(true, test.opts...), msg)
+			for _, s := range test.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("missing %q in %q", s, out)
+				}
+			}
+			for _, s := range test.exclude {
+				if strings.Contains(out, s) {
+					t.Errorf("unexpected %q in %q", s, out)
+				}
+			}
+		})
+	}
+
+	t.Run("no color", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		t.Setenv("FORCE_COLOR", "1")
+
+		if out := logString(t, Console(true), msg); strings.Contains(out, "\x1b[") {
+			t.Errorf("unexpected escape sequence in %q", out)
+		}
+	})
+}
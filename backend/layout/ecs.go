@@ -0,0 +1,109 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"path"
+	"runtime/debug"
+	"strings"
+
+	"github.com/urso/diag"
+	"github.com/urso/diag-ecs/ecs"
+)
+
+// mainModulePath is the module path of the main module, if the binary has
+// been built with module support.
+var mainModulePath = func() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Path
+	}
+	return ""
+}()
+
+// ECS creates a JSON layout that always reports the ECS base fields. In
+// addition to the fields written by the JSON layout, the documents include
+// `@timestamp` from the event, `ecs.version`, and `log.origin.function`.
+func ECS(fields []diag.Field, opts ...Option) Factory {
+	preset := []Option{
+		EventTimestamp(),
+		ECSVersion(ecs.Version),
+		OriginFunction(),
+	}
+	return JSON(fields, append(preset, opts...)...)
+}
+
+// EventTimestamp configures the structured layout to report the timestamp of
+// the event as `@timestamp`, formatted as RFC3339 with nanoseconds in UTC.
+func EventTimestamp() Option {
+	return func(o *options) error {
+		o.eventTimestamp = true
+		return nil
+	}
+}
+
+// ECSVersion configures the structured layout to report version in the
+// `ecs.version` field.
+func ECSVersion(version string) Option {
+	return func(o *options) error {
+		o.ecsVersion = version
+		return nil
+	}
+}
+
+// OriginFunction configures the structured layout to report the function
+// name of the caller in `log.origin.function`.
+func OriginFunction() Option {
+	return func(o *options) error {
+		o.originFunction = true
+		return nil
+	}
+}
+
+// ModuleRelativePaths configures the structured layout to report the path of
+// the caller's source file relative to the module root in
+// `log.origin.file.name`, instead of the absolute path on the build host.
+// Source files in other modules are reported with the import path of the
+// package. Source files in package main are reported with the name of the
+// directory only, as the package directory within the module is unknown.
+func ModuleRelativePaths() Option {
+	return func(o *options) error {
+		o.relPaths = true
+		return nil
+	}
+}
+
+// moduleRelativePath creates the module relative path of a source file.
+// The package import path is taken from the function name.
+func moduleRelativePath(file, function string) string {
+	file = strings.Replace(file, "\\", "/", -1)
+	base := path.Base(file)
+
+	pkg := funcPackage(function)
+	switch {
+	case pkg == "" || pkg == "main":
+		return path.Join(path.Base(path.Dir(file)), base)
+	case mainModulePath != "" && pkg == mainModulePath:
+		return base
+	case mainModulePath != "" && strings.HasPrefix(pkg, mainModulePath+"/"):
+		return path.Join(pkg[len(mainModulePath)+1:], base)
+	default:
+		return path.Join(pkg, base)
+	}
+}
+
+// funcPackage extracts the package import path from a fully qualified
+// function name, like `github.com/urso/ecslog.(*Logger).Info`. Dots in the
+// last element of the import path are escaped as `%2e` in function names,
+// like in `gopkg.in/yaml%2ev2.Unmarshal`.
+func funcPackage(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return strings.Replace(function[:slash+1+dot], "%2e", ".", -1)
+}
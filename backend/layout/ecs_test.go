@@ -0,0 +1,94 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urso/diag-ecs/ecs"
+
+	"github.com/urso/ecslog/backend"
+)
+
+func TestECS(t *testing.T) {
+	msg := testMessage("hello")
+	msg.Timestamp = time.Date(2020, 1, 2, 3, 4, 5, 6, time.FixedZone("test", 3600))
+	msg.Caller = backend.GetCaller(0)
+
+	event := logJSON(t, ECS(nil), msg)
+
+	expected := map[string]interface{}{
+		"@timestamp":  "2020-01-02T02:04:05.000000006Z",
+		"ecs.version": ecs.Version,
+		"message":     "hello",
+		"log.logger":  "test",
+		"log.level":   "info",
+	}
+	for key, want := range expected {
+		if got, _ := lookup(event, key); got != want {
+			t.Errorf("%v: got %v, want %v", key, got, want)
+		}
+	}
+
+	fn, _ := lookup(event, "log.origin.function")
+	if s, _ := fn.(string); !strings.HasSuffix(s, "/backend/layout.TestECS") {
+		t.Errorf("unexpected log.origin.function: %v", fn)
+	}
+}
+
+func TestEventTimeDefault(t *testing.T) {
+	before := time.Now()
+	ts := eventTime(testMessage("hello"))
+	if ts.Before(before) || ts.After(time.Now()) {
+		t.Errorf("expected current time if timestamp is not set, got %v", ts)
+	}
+
+	want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	msg := testMessage("hello")
+	msg.Timestamp = want
+	if ts := eventTime(msg); !ts.Equal(want) {
+		t.Errorf("got %v, want %v", ts, want)
+	}
+}
+
+func TestFuncPackage(t *testing.T) {
+	cases := map[string]string{
+		"main.main":                                "main",
+		"github.com/urso/ecslog.(*Logger).Info":    "github.com/urso/ecslog",
+		"github.com/urso/ecslog/backend.GetCaller": "github.com/urso/ecslog/backend",
+		"gopkg.in/yaml%2ev2.Unmarshal":             "gopkg.in/yaml.v2",
+		"github.com/a/b%2ec.(*T).M.func1":          "github.com/a/b.c",
+		"noqualifier":                              "",
+	}
+	for function, want := range cases {
+		if got := funcPackage(function); got != want {
+			t.Errorf("%v: got %q, want %q", function, got, want)
+		}
+	}
+}
+
+func TestModuleRelativePath(t *testing.T) {
+	defer func(path string) { mainModulePath = path }(mainModulePath)
+	mainModulePath = "github.com/urso/app"
+
+	cases := []struct {
+		file, function, want string
+	}{
+		{"/src/app/main.go", "main.main", "app/main.go"},
+		{`C:\src\app\main.go`, "main.main", "app/main.go"},
+		{"/src/app/app.go", "github.com/urso/app.Run", "app.go"},
+		{"/src/app/internal/db/db.go", "github.com/urso/app/internal/db.Open", "internal/db/db.go"},
+		{"/go/pkg/mod/gopkg.in/yaml.v2@v2.4.0/yaml.go", "gopkg.in/yaml%2ev2.Unmarshal", "gopkg.in/yaml.v2/yaml.go"},
+	}
+	for _, test := range cases {
+		if got := moduleRelativePath(test.file, test.function); got != test.want {
+			t.Errorf("%v: got %q, want %q", test.file, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"reflect"
+	"time"
+)
+
+// ValueEncoder converts a field value into the value reported by the layout.
+// The value returned is serialized as usual, so both the text and structured
+// layouts report the same representation.
+type ValueEncoder func(v interface{}) interface{}
+
+// valueEncoders configures how field values of special types are reported.
+type valueEncoders struct {
+	types      map[reflect.Type]ValueEncoder
+	marshalers []Marshaler // marshaler interfaces checked, by priority
+}
+
+var (
+	// DurationNanos reports a time.Duration as number of nanoseconds.
+	DurationNanos ValueEncoder = func(v interface{}) interface{} {
+		return int64(v.(time.Duration))
+	}
+
+	// DurationString reports a time.Duration as string, like `1.5s`.
+	DurationString ValueEncoder = func(v interface{}) interface{} {
+		return v.(time.Duration).String()
+	}
+
+	// TimeRFC3339Nano reports a time.Time as RFC3339 formatted string with
+	// nanoseconds.
+	TimeRFC3339Nano ValueEncoder = func(v interface{}) interface{} {
+		return v.(time.Time).Format(time.RFC3339Nano)
+	}
+
+	// TimeEpochMillis reports a time.Time as milliseconds since the Unix
+	// epoch.
+	TimeEpochMillis ValueEncoder = func(v interface{}) interface{} {
+		return v.(time.Time).UnixNano() / int64(time.Millisecond)
+	}
+
+	// BytesBase64 reports a []byte as base64 encoded string.
+	BytesBase64 ValueEncoder = func(v interface{}) interface{} {
+		return base64.StdEncoding.EncodeToString(v.([]byte))
+	}
+
+	// BytesHex reports a []byte as hex encoded string.
+	BytesHex ValueEncoder = func(v interface{}) interface{} {
+		return hex.EncodeToString(v.([]byte))
+	}
+
+	// AsString reports a value as string, using the String method if the
+	// value implements fmt.Stringer.
+	AsString ValueEncoder = func(v interface{}) interface{} {
+		if s, ok := v.(fmt.Stringer); ok {
+			return s.String()
+		}
+		return fmt.Sprint(v)
+	}
+)
+
+// EncodeValues registers an encoder for all values of the same type as
+// sample. Encoders are applied to field values only, values nested in maps
+// or structs are serialized as is.
+//
+//	layout.EncodeValues(time.Duration(0), layout.DurationString)
+func EncodeValues(sample interface{}, enc ValueEncoder) Option {
+	return func(o *options) error {
+		if sample == nil || enc == nil {
+			return fmt.Errorf("sample value and encoder must not be nil")
+		}
+
+		types := make(map[reflect.Type]ValueEncoder, len(o.encoders.types)+1)
+		for t, e := range o.encoders.types {
+			types[t] = e
+		}
+		types[reflect.TypeOf(sample)] = enc
+		o.encoders.types = types
+		return nil
+	}
+}
+
+// StandardEncoders registers encoders for common types, such that all
+// layouts agree on their representation. Durations are reported in
+// nanoseconds, timestamps as RFC3339 with nanoseconds, byte slices as base64,
+// and IP addresses and big integers as strings.
+func StandardEncoders() Option {
+	return func(o *options) error {
+		for _, opt := range []Option{
+			EncodeValues(time.Duration(0), DurationNanos),
+			EncodeValues(time.Time{}, TimeRFC3339Nano),
+			EncodeValues([]byte(nil), BytesBase64),
+			EncodeValues(net.IP(nil), AsString),
+			EncodeValues((*big.Int)(nil), AsString),
+		} {
+			if err := opt(o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// encode applies the encoder registered for the type of v. The value is
+// returned as is if no encoder is registered.
+func (e valueEncoders) encode(v interface{}) interface{} {
+	if len(e.types) == 0 || v == nil {
+		return v
+	}
+	if enc, exists := e.types[reflect.TypeOf(v)]; exists {
+		return enc(v)
+	}
+	return v
+}
@@ -0,0 +1,144 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testID int
+
+type testText struct{ v string }
+
+func (t testText) MarshalText() ([]byte, error) { return []byte("text:" + t.v), nil }
+
+func TestEncodersStringLayouts(t *testing.T) {
+	opts := []Option{
+		EncodeValues(testID(0), func(v interface{}) interface{} {
+			return "id-" + strings.Repeat("x", int(v.(testID)))
+		}),
+	}
+
+	layouts := map[string]Factory{
+		"gelf":    GELF("host", nil, opts...),
+		"pretty":  Pretty(opts...),
+		"syslog":  Syslog(SyslogConfig{Hostname: "host"}, opts...),
+		"pattern": Pattern("%X{code} %X{text}", opts...),
+	}
+
+	for name, factory := range layouts {
+		factory := factory
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("hello", "code", testID(3), "text", testText{"a"})
+			out := logString(t, factory, msg)
+			for _, want := range []string{"id-xxx", "text:a"} {
+				if !strings.Contains(out, want) {
+					t.Errorf("missing %q in %q", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeString(t *testing.T) {
+	enc := valueEncoders{
+		types: map[reflect.Type]ValueEncoder{
+			reflect.TypeOf(testID(0)): func(v interface{}) interface{} { return []byte("id") },
+		},
+		marshalers: defaultMarshalers,
+	}
+
+	cases := map[string]struct {
+		in   interface{}
+		want string
+	}{
+		"string":   {"a b", "a b"},
+		"bytes":    {[]byte("raw"), "raw"},
+		"number":   {42, "42"},
+		"text":     {testText{"x"}, "text:x"},
+		"slice":    {[]int{1, 2}, "[1 2]"},
+		"nil":      {nil, "<nil>"},
+		"encoded":  {testID(1), "id"},
+		"truncate": {testText{strings.Repeat("x", 10)}, "text:x" + truncatedMarker},
+	}
+
+	for name, test := range cases {
+		limits := Limits{}
+		if name == "truncate" {
+			limits.MaxStringLen = 6
+		}
+		got, err := enc.encodeString(test.in, limits)
+		if err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%v: got %q, want %q", name, got, test.want)
+		}
+	}
+}
+
+// TestEncodersAgree checks that the JSON and text layouts report the same
+// value for the standard encoders.
+func TestEncodersAgree(t *testing.T) {
+	ts := time.Date(2020, 3, 14, 15, 9, 26, 535e6, time.UTC)
+
+	cases := map[string]struct {
+		value   interface{}
+		encoder ValueEncoder
+		want    string
+	}{
+		"DurationNanos":   {1500 * time.Millisecond, DurationNanos, "1500000000"},
+		"DurationString":  {1500 * time.Millisecond, DurationString, "1.5s"},
+		"TimeEpochMillis": {ts, TimeEpochMillis, "1584198566535"},
+		"BytesHex":        {[]byte{0x0a, 0xff}, BytesHex, "0aff"},
+	}
+
+	textValue := regexp.MustCompile(`\| v=("(?:[^"\\]|\\.)*"|\S+)`)
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			opt := EncodeValues(test.value, test.encoder)
+			msg := testMessage("test", "v", test.value)
+
+			event := logJSON(t, JSON(nil, opt), msg)
+			raw, ok := lookup(event, "fields.v")
+			if !ok {
+				t.Fatalf("missing fields.v in %v", event)
+			}
+			var jsonValue string
+			switch v := raw.(type) {
+			case float64:
+				jsonValue = strconv.FormatFloat(v, 'f', -1, 64)
+			case string:
+				jsonValue = v
+			default:
+				t.Fatalf("unexpected JSON value %#v", raw)
+			}
+
+			out := logString(t, Text(true, opt), msg)
+			m := textValue.FindStringSubmatch(out)
+			if m == nil {
+				t.Fatalf("missing v in %q", out)
+			}
+			text := m[1]
+			if unquoted, err := strconv.Unquote(text); err == nil {
+				text = unquoted
+			}
+
+			if jsonValue != test.want || text != test.want {
+				t.Errorf("JSON reports %q, text reports %q, want %q", jsonValue, text, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/urso/sderr"
+)
+
+// maxErrorDepth is the default limit of nested causes reported for an error.
+const maxErrorDepth = 32
+
+// multiUnwrapper is implemented by errors combining multiple errors, like
+// the errors created by errors.Join (Go 1.20+).
+type multiUnwrapper interface {
+	Unwrap() []error
+}
+
+// callerser is implemented by errors capturing the program counters of the
+// call stack, as returned by runtime.Callers.
+type callerser interface {
+	Callers() []uintptr
+}
+
+var sderrPkgPath = reflect.TypeOf(sderr.StackTrace(nil)).PkgPath()
+
+// errPath records the chain of errors from the logged error to the current
+// cause. It is used to protect against cycles and very deep error trees.
+type errPath struct {
+	parent *errPath
+	err    error
+	depth  int
+	max    int // maximum depth, 0 if unlimited
+
+	// trace holds the stack trace reported for err, if any.
+	trace []uintptr
+}
+
+// newErrPath creates an empty path, limiting the depth of the error chain to
+// max causes. The depth is unlimited if max is 0.
+func newErrPath(max int) *errPath {
+	return &errPath{depth: -1, max: max}
+}
+
+// errNumCauses returns the number of direct causes of err. In addition to the
+// interfaces supported by sderr, errors implementing `Unwrap() []error` are
+// supported.
+func errNumCauses(err error) int {
+	if n := sderr.NumCauses(err); n > 0 {
+		return n
+	}
+	if me, ok := err.(multiUnwrapper); ok {
+		return len(me.Unwrap())
+	}
+	return 0
+}
+
+// errCause returns the i-th direct cause of err.
+func errCause(err error, i int) error {
+	if sderr.NumCauses(err) > 0 {
+		return sderr.Cause(err, i)
+	}
+	if me, ok := err.(multiUnwrapper); ok {
+		if errs := me.Unwrap(); i < len(errs) {
+			return errs[i]
+		}
+	}
+	return nil
+}
+
+// errUnwrap returns the first direct cause of err.
+func errUnwrap(err error) error {
+	if cause := sderr.Unwrap(err); cause != nil {
+		return cause
+	}
+	if me, ok := err.(multiUnwrapper); ok {
+		if errs := me.Unwrap(); len(errs) > 0 {
+			return errs[0]
+		}
+	}
+	return nil
+}
+
+// push creates a new path with err being the last error visited.
+func (p *errPath) push(err error) *errPath {
+	if p == nil {
+		p = newErrPath(maxErrorDepth)
+	}
+	return &errPath{parent: p, err: err, depth: p.depth + 1, max: p.max}
+}
+
+// follow checks if the cause of the last error in the path should be
+// reported. It returns false if the maximum depth has been reached, or if
+// the cause has already been visited.
+func (p *errPath) follow(cause error) bool {
+	if cause == nil {
+		return false
+	}
+	if p == nil {
+		return true
+	}
+	if p.atLimit() {
+		return false
+	}
+
+	if !reflect.TypeOf(cause).Comparable() {
+		return true
+	}
+	for cur := p; cur != nil; cur = cur.parent {
+		if cur.err != nil && sameError(cur.err, cause) {
+			return false
+		}
+	}
+	return true
+}
+
+// atLimit checks if the path has reached the maximum depth, such that no
+// more causes are reported.
+func (p *errPath) atLimit() bool {
+	return p != nil && p.max > 0 && p.depth+1 >= p.max
+}
+
+// tracedBefore checks if a stack trace with the same frames as pcs has
+// already been reported by an error in the path.
+func (p *errPath) tracedBefore(pcs []uintptr) bool {
+	for cur := p; cur != nil; cur = cur.parent {
+		if samePCs(cur.trace, pcs) {
+			return true
+		}
+	}
+	return false
+}
+
+func samePCs(a, b []uintptr) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameError(a, b error) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	return ta == tb && ta.Comparable() && a == b
+}
+
+// errType reports the Go type name of err. If root is set, wrappers created
+// by sderr are unwrapped, so to report the type of the wrapped cause.
+func errType(err error, root bool) string {
+	if root {
+		var path *errPath
+		for isSderrValue(err) && errNumCauses(err) == 1 {
+			path = path.push(err)
+			cause := errUnwrap(err)
+			if !path.follow(cause) {
+				break
+			}
+			err = cause
+		}
+	}
+	return reflect.TypeOf(err).String()
+}
+
+func isSderrValue(err error) bool {
+	t := reflect.TypeOf(err)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath() == sderrPkgPath
+}
+
+// errChainStackPCs returns the stack trace of the deepest error in the
+// linear chain of causes of err that exposes a stack trace.
+func errChainStackPCs(err error) []uintptr {
+	var path *errPath
+	var pcs []uintptr
+	for err != nil {
+		if tmp := errStackPCs(err); len(tmp) > 0 {
+			pcs = tmp
+		}
+		if errNumCauses(err) != 1 {
+			break
+		}
+
+		path = path.push(err)
+		cause := errUnwrap(err)
+		if !path.follow(cause) {
+			break
+		}
+		err = cause
+	}
+	return pcs
+}
+
+// errStackPCs extracts the program counters of a stack trace from err.
+// Supported are errors created by sderr, errors implementing `Callers()
+// []uintptr`, and errors implementing a `StackTrace()` method returning a
+// slice of uintptr based frames, like github.com/pkg/errors.
+func errStackPCs(err error) []uintptr {
+	switch v := err.(type) {
+	case interface{ StackTrace() sderr.StackTrace }:
+		st := v.StackTrace()
+		pcs := make([]uintptr, len(st))
+		for i, frame := range st {
+			pcs[i] = uintptr(frame)
+		}
+		return pcs
+
+	case callerser:
+		return v.Callers()
+	}
+
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() {
+		return nil
+	}
+	if mt := m.Type(); mt.NumIn() != 0 || mt.NumOut() != 1 {
+		return nil
+	}
+
+	frames := m.Call(nil)[0]
+	if frames.Kind() != reflect.Slice || frames.Type().Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	pcs := make([]uintptr, frames.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+	return pcs
+}
+
+func formatStackTrace(pcs []uintptr) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(&buf, "%v\n\t%v:%v", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,248 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/urso/sderr"
+)
+
+// chainErr is a linked list of errors. The list can contain cycles.
+type chainErr struct {
+	msg  string
+	next error
+}
+
+func (e *chainErr) Error() string { return e.msg }
+func (e *chainErr) Unwrap() error { return e.next }
+
+func TestErrUnwrap(t *testing.T) {
+	inner := fmt.Errorf("middle: %w", os.ErrNotExist)
+	wrapped := fmt.Errorf("outer: %w", inner)
+	if got := errUnwrap(wrapped); got != inner {
+		t.Errorf("unwrap: got %v, want %v", got, inner)
+	}
+	if n := errNumCauses(wrapped); n != 1 {
+		t.Errorf("got %v causes, want 1", n)
+	}
+
+	a, b := errors.New("a"), errors.New("b")
+	joined := errors.Join(a, b)
+	if n := errNumCauses(joined); n != 2 {
+		t.Fatalf("got %v causes, want 2", n)
+	}
+	if errCause(joined, 0) != a || errCause(joined, 1) != b {
+		t.Error("unexpected causes of joined error")
+	}
+	if errCause(joined, 2) != nil {
+		t.Error("expected nil for cause out of range")
+	}
+	if got := errUnwrap(joined); got != a {
+		t.Errorf("unwrap joined: got %v, want %v", got, a)
+	}
+}
+
+func TestErrorChain(t *testing.T) {
+	msg := testMessage("failed")
+	msg.Causes = []error{fmt.Errorf("outer: %w", fmt.Errorf("middle: %w", os.ErrNotExist))}
+
+	event := logJSON(t, JSON(nil), msg)
+	expect := map[string]interface{}{
+		"error.message":             "outer: middle: file does not exist",
+		"error.cause.message":       "middle: file does not exist",
+		"error.cause.cause.message": "file does not exist",
+	}
+	for key, want := range expect {
+		if got, _ := lookup(event, key); got != want {
+			t.Errorf("%v: got %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestErrorJoin(t *testing.T) {
+	msg := testMessage("failed")
+	msg.Causes = []error{errors.Join(errors.New("a"), fmt.Errorf("b: %w", os.ErrClosed))}
+
+	event := logJSON(t, JSON(nil), msg)
+	causes, _ := lookup(event, "error.causes")
+	list, ok := causes.([]interface{})
+	if !ok || len(list) != 2 {
+		t.Fatalf("expected 2 causes, got %v", causes)
+	}
+	second := list[1].(map[string]interface{})
+	if got, _ := lookup(second, "cause.message"); got != os.ErrClosed.Error() {
+		t.Errorf("nested cause of joined error: got %v", got)
+	}
+}
+
+func TestErrorCycle(t *testing.T) {
+	a := &chainErr{msg: "a"}
+	b := &chainErr{msg: "b", next: a}
+	a.next = b
+
+	msg := testMessage("failed")
+	msg.Causes = []error{a}
+
+	event := logJSON(t, JSON(nil), msg)
+	if got, _ := lookup(event, "error.cause.message"); got != "b" {
+		t.Errorf("cause: got %v, want b", got)
+	}
+	if _, ok := lookup(event, "error.cause.cause"); ok {
+		t.Error("cycle has been followed")
+	}
+
+	out := logString(t, Text(true), msg)
+	if n := strings.Count(out, "\n"); n != 3 {
+		t.Errorf("expected message and 2 errors, got:\n%v", out)
+	}
+}
+
+func TestErrorDepthLimit(t *testing.T) {
+	var err error = errors.New("root cause")
+	for i := 0; i < 2*maxErrorDepth; i++ {
+		err = &chainErr{msg: fmt.Sprintf("level %d", i), next: err}
+	}
+
+	msg := testMessage("failed")
+	msg.Causes = []error{err}
+
+	event := logJSON(t, JSON(nil), msg)
+	key, depth := "error.cause", 0
+	for {
+		if _, ok := lookup(event, key); !ok {
+			break
+		}
+		key += ".cause"
+		depth++
+	}
+	if depth != maxErrorDepth-1 {
+		t.Errorf("got %v nested causes, want %v", depth, maxErrorDepth-1)
+	}
+}
+
+type testError struct{}
+
+func (testError) Error() string { return "test error" }
+
+// traceErr reports a fixed stack trace via Callers.
+type traceErr struct {
+	chainErr
+	pcs []uintptr
+}
+
+func (e *traceErr) Callers() []uintptr { return e.pcs }
+
+func traceA() []uintptr { return callers() }
+func traceB() []uintptr { return callers() }
+
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	return pcs[:runtime.Callers(2, pcs)]
+}
+
+func TestErrorType(t *testing.T) {
+	err := sderr.Wrap(testError{}, "wrapped")
+
+	cases := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, "*sderr.wrappedErrValue"},
+		{"root", []Option{RootErrorType()}, "layout.testError"},
+	}
+	for _, test := range cases {
+		msg := testMessage("failed")
+		msg.Causes = []error{err}
+
+		event := logJSON(t, JSON(nil, test.opts...), msg)
+		if got, _ := lookup(event, "error.type"); got != test.want {
+			t.Errorf("%v: got error.type %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestErrorStackTrace(t *testing.T) {
+	a, b := traceA(), traceB()
+	chain := func(traces ...[]uintptr) error {
+		var err error
+		for i := len(traces) - 1; i >= 0; i-- {
+			msg := fmt.Sprintf("err%v", i)
+			if traces[i] == nil {
+				err = &chainErr{msg: msg, next: err}
+			} else {
+				err = &traceErr{chainErr{msg: msg, next: err}, traces[i]}
+			}
+		}
+		return err
+	}
+
+	cases := map[string]struct {
+		err    error
+		expect map[string]string // key -> function expected in the trace, "" if no trace
+	}{
+		"different traces": {
+			err: chain(a, b),
+			expect: map[string]string{
+				"error.stack_trace":       "traceA",
+				"error.cause.stack_trace": "traceB",
+			},
+		},
+		"identical traces": {
+			err: chain(a, a, b),
+			expect: map[string]string{
+				"error.stack_trace":             "traceA",
+				"error.cause.stack_trace":       "",
+				"error.cause.cause.stack_trace": "traceB",
+			},
+		},
+		"repeated after other trace": {
+			err: chain(a, b, a),
+			expect: map[string]string{
+				"error.stack_trace":             "traceA",
+				"error.cause.stack_trace":       "traceB",
+				"error.cause.cause.stack_trace": "",
+			},
+		},
+		"no trace at top": {
+			err: chain(nil, b),
+			expect: map[string]string{
+				"error.stack_trace":       "traceB",
+				"error.cause.stack_trace": "",
+			},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("failed")
+			msg.Causes = []error{test.err}
+
+			event := logJSON(t, JSON(nil), msg)
+			for key, fn := range test.expect {
+				got, exists := lookup(event, key)
+				if fn == "" {
+					if exists {
+						t.Errorf("unexpected %v: %v", key, got)
+					}
+					continue
+				}
+
+				if trace, _ := got.(string); !strings.Contains(trace, "layout."+fn) {
+					t.Errorf("expected %v to contain %v, got: %v", key, fn, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// RawText disables the escaping of control characters in the text, pattern,
+// and pretty layouts, such that messages and values are written as is. It
+// must only be used if all messages and values are trusted.
+//
+// By default, the layouts escape control characters in messages, keys, and
+// unquoted values, for all outputs including terminals. Escaping prevents
+// untrusted input from forging log lines via newlines, or from manipulating
+// terminals via ANSI escape sequences. Newlines, tabs, and carriage returns
+// are written as `\n`, `\t`, and `\r`. All other C0 and C1 control
+// characters, including the ESC starting ANSI escape sequences, are written
+// as `\x1b` or `\u0085`. Backslashes are written as `\\`, such that escaped
+// characters can be told apart from the input.
+func RawText() Option {
+	return func(o *options) error {
+		o.rawText = true
+		return nil
+	}
+}
+
+// escapeText returns s with all control characters and backslashes being
+// escaped, if escape is set.
+func escapeText(s string, escape bool) string {
+	if !escape || !needsEscape(s) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for _, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < utf8.RuneSelf && isControl(r):
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case isControl(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func needsEscape(s string) bool {
+	for _, r := range s {
+		if r == '\\' || isControl(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isControl checks for C0 and C1 control characters, DEL, and the Unicode
+// line and paragraph separators.
+func isControl(r rune) bool {
+	return r < 0x20 || (r >= 0x7f && r <= 0x9f) || r == '\u2028' || r == '\u2029'
+}
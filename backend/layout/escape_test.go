@@ -0,0 +1,95 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// rawJSON reports a JSON document with control characters, which the text
+// layouts print unquoted.
+type rawJSON string
+
+func (r rawJSON) MarshalJSON() ([]byte, error) { return []byte(r), nil }
+
+func TestEscapeText(t *testing.T) {
+	cases := map[string]struct {
+		in, want string
+	}{
+		"plain":     {"hello", "hello"},
+		"newline":   {"a\nb\r\n", `a\nb\r\n`},
+		"tab":       {"a\tb", `a\tb`},
+		"ansi":      {"\x1b[2J", `\x1b[2J`},
+		"del":       {"a\x7f", `a\x7f`},
+		"c1":        {"a\u0085b\u009b", `a\u0085b\u009b`},
+		"separator": {"a\u2028b", `a\u2028b`},
+		"backslash": {`a\nb`, `a\\nb`},
+		"unicode":   {"héllo", "héllo"},
+	}
+
+	for name, test := range cases {
+		if got := escapeText(test.in, true); got != test.want {
+			t.Errorf("%v: got %q, want %q", name, got, test.want)
+		}
+		if got := escapeText(test.in, false); got != test.in {
+			t.Errorf("%v: raw text modified: %q", name, got)
+		}
+	}
+}
+
+func TestEscapeInjection(t *testing.T) {
+	const forged = "\nERROR forged line"
+
+	msg := testMessage("login"+forged+"\x1b[2J\u0085\u2028",
+		"user"+forged, "bob"+forged,
+		"doc", rawJSON(`{"a":1}`+forged),
+		"map", map[string]int{"k" + forged: 1},
+	)
+	msg.Name = "name" + forged
+	msg.Causes = []error{errors.New("failed" + forged)}
+
+	layouts := map[string]Factory{
+		"text":    Text(true),
+		"pattern": Pattern("%c %m %X{user} %X{doc} %err%n"),
+		"logfmt":  Logfmt(nil),
+		"pretty":  Pretty(),
+	}
+
+	for name, factory := range layouts {
+		factory := factory
+		t.Run(name, func(t *testing.T) {
+			out := logString(t, factory, msg)
+			if strings.Contains(out, forged) {
+				t.Errorf("forged line in output:\n%v", out)
+			}
+			if strings.ContainsAny(out, "\x1b\u0085\u2028") {
+				t.Errorf("control characters in output: %q", out)
+			}
+		})
+	}
+}
+
+func TestEscapeRawText(t *testing.T) {
+	msg := testMessage("a\nb\x1b[0m", "v", rawJSON("1\n"))
+
+	layouts := map[string]Factory{
+		"text":    Text(true, RawText()),
+		"pattern": Pattern("%m%n", RawText()),
+		"pretty":  Pretty(RawText()),
+	}
+
+	for name, factory := range layouts {
+		factory := factory
+		t.Run(name, func(t *testing.T) {
+			if out := logString(t, factory, msg); !strings.Contains(out, "b\x1b[0m") {
+				t.Errorf("message modified: %q", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/urso/diag"
+)
+
+// FieldFilter selects the context fields reported by a layout. Fields set by
+// the layout itself, like `message` or `log.level`, are always reported.
+//
+// Patterns are matched against flattened keys, using the same syntax as
+// Redaction.Keys. Keys are matched as reported by the structured layout.
+// Standardized fields are matched by their key. User fields are matched
+// within the namespace configured via UserFieldsNamespace, such that
+// `fields` or `fields.**` selects all user fields by default. User fields
+// reported as ECS labels are matched as `labels.*`. A pattern selects a
+// field if it matches the key or any parent of the key, e.g. `http.request`
+// selects `http.request.method`.
+//
+// The filter is applied to the log context and to the contexts of errors.
+type FieldFilter struct {
+	// Include lists the fields to be reported. All fields are included if
+	// Include is empty.
+	Include []string
+
+	// Exclude lists fields to be removed. Exclude takes precedence over
+	// Include.
+	Exclude []string
+}
+
+type fieldFilter struct {
+	include [][]string
+	exclude [][]string
+
+	// namespace user fields are reported in, split into segments
+	namespace []string
+	labels    bool
+}
+
+// labelsNamespace is the namespace of user fields reported as ECS labels.
+var labelsNamespace = []string{"labels"}
+
+// FilterFields configures the layout to report only fields selected by f.
+func FilterFields(f FieldFilter) Option {
+	return func(o *options) error {
+		filter, err := newFieldFilter(f)
+		if err != nil {
+			return err
+		}
+		o.filter = filter
+		return nil
+	}
+}
+
+// ContextFilter creates a function removing the fields not selected by f
+// from a context. It can be used by backends filtering messages before they
+// are passed to a layout. The keys of user fields are matched within
+// userNamespace, which must match the namespace configured in the layout.
+// The context is returned as is if all fields are selected.
+func ContextFilter(f FieldFilter, userNamespace string) (func(*diag.Context) *diag.Context, error) {
+	filter, err := newFieldFilter(f)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return func(ctx *diag.Context) *diag.Context { return ctx }, nil
+	}
+
+	filter.setUserFields(&userFieldsMapping{namespace: userNamespace})
+	return filter.context, nil
+}
+
+// newFieldFilter parses the patterns of f. The filter is nil if f has no
+// patterns.
+func newFieldFilter(f FieldFilter) (*fieldFilter, error) {
+	include, err := parseKeyPatterns(f.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := parseKeyPatterns(f.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	return &fieldFilter{
+		include:   include,
+		exclude:   exclude,
+		namespace: []string{defaultUserNamespace},
+	}, nil
+}
+
+// setUserFields configures the keys user fields are matched with, once all
+// layout options have been applied.
+func (f *fieldFilter) setUserFields(m *userFieldsMapping) {
+	f.namespace = nil
+	if m.namespace != "" {
+		f.namespace = strings.Split(m.namespace, ".")
+	}
+	f.labels = m.labels
+}
+
+func parseKeyPatterns(patterns []string) ([][]string, error) {
+	var parsed [][]string
+	for _, pattern := range patterns {
+		segments := strings.Split(pattern, ".")
+		for _, seg := range segments {
+			if _, err := path.Match(seg, ""); err != nil {
+				return nil, fmt.Errorf("invalid key pattern '%v': %v", pattern, err)
+			}
+		}
+		parsed = append(parsed, segments)
+	}
+	return parsed, nil
+}
+
+// context returns a copy of ctx with all fields not selected by the filter
+// being removed. The original context is returned if all fields are
+// selected.
+func (f *fieldFilter) context(ctx *diag.Context) *diag.Context {
+	if f == nil || ctx.Len() == 0 {
+		return ctx
+	}
+
+	std, user := ctx.Standardized(), ctx.User()
+	if f.keepAll(std, true) && f.keepAll(user, false) {
+		return ctx
+	}
+
+	filtered := diag.NewContext(nil, nil)
+	f.copyFields(filtered, std, true)
+	f.copyFields(filtered, user, false)
+	return filtered
+}
+
+func (f *fieldFilter) keepAll(ctx *diag.Context, std bool) bool {
+	keep := true
+	visitFlatFields(ctx, "", func(key string, v diag.Value) error {
+		if keep = f.keep(key, v, std); !keep {
+			return errStopVisit
+		}
+		return nil
+	})
+	return keep
+}
+
+func (f *fieldFilter) copyFields(to, from *diag.Context, std bool) {
+	visitFlatFields(from, "", func(key string, v diag.Value) error {
+		if f.keep(key, v, std) {
+			to.AddField(diag.Field{Key: key, Value: v, Standardized: std})
+		}
+		return nil
+	})
+}
+
+// keep checks if the field is selected. User fields are matched with the
+// namespace they are reported in.
+func (f *fieldFilter) keep(key string, v diag.Value, std bool) bool {
+	var ns []string
+	if !std {
+		ns = f.namespace
+		if f.labels && isLabel(key, v) {
+			ns = labelsNamespace
+		}
+	}
+
+	if len(f.include) > 0 && !matchKeyOrParent(f.include, ns, key) {
+		return false
+	}
+	return !matchKeyOrParent(f.exclude, ns, key)
+}
+
+func matchKeyOrParent(patterns [][]string, ns []string, key string) bool {
+	for _, pattern := range patterns {
+		if matchPrefix(pattern, ns, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPrefix checks if pattern matches the key or any parent of the key.
+// The key is made up of the segments in ns followed by the dotted key. The
+// key is not split into segments upfront, so to not allocate when filtering
+// events.
+func matchPrefix(pattern, ns []string, key string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+
+	if pattern[0] == "**" {
+		for {
+			if matchPrefix(pattern[1:], ns, key) {
+				return true
+			}
+
+			if len(ns) > 0 {
+				ns = ns[1:]
+			} else if i := strings.IndexByte(key, '.'); i >= 0 {
+				key = key[i+1:]
+			} else {
+				return false
+			}
+		}
+	}
+
+	var seg string
+	switch {
+	case len(ns) > 0:
+		seg, ns = ns[0], ns[1:]
+	case key != "":
+		seg, key = key, ""
+		if i := strings.IndexByte(seg, '.'); i >= 0 {
+			seg, key = seg[:i], seg[i+1:]
+		}
+	default:
+		return false
+	}
+
+	if ok, _ := path.Match(pattern[0], seg); !ok {
+		return false
+	}
+	return matchPrefix(pattern[1:], ns, key)
+}
+
+// visitFlatFields reports all fields in ctx with their fully qualified key.
+// Nested contexts are flattened into dotted keys.
+func visitFlatFields(ctx *diag.Context, prefix string, fn func(key string, v diag.Value) error) error {
+	return ctx.VisitKeyValues(visitValues(func(key string, v diag.Value) error {
+		if nested, ok := v.Ifc.(*diag.Context); ok {
+			return visitFlatFields(nested, prefix+key+".", fn)
+		}
+		return fn(prefix+key, v)
+	}))
+}
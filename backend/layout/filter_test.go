@@ -0,0 +1,176 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/urso/diag"
+	"github.com/urso/diag-ecs/ecs"
+	"github.com/urso/sderr"
+)
+
+func TestFilterFields(t *testing.T) {
+	cases := map[string]struct {
+		opts    []Option
+		present []string
+		missing []string
+	}{
+		"include": {
+			opts:    []Option{FilterFields(FieldFilter{Include: []string{"http", "fields.id"}})},
+			present: []string{"http.request.method", "fields.id", "message", "log.level"},
+			missing: []string{"host", "fields.app", "fields.name"},
+		},
+		"exclude user fields": {
+			opts:    []Option{FilterFields(FieldFilter{Exclude: []string{"fields"}})},
+			present: []string{"http.request.method", "host.hostname"},
+			missing: []string{"fields"},
+		},
+		"exclude takes precedence": {
+			opts:    []Option{FilterFields(FieldFilter{Include: []string{"**"}, Exclude: []string{"**.method"}})},
+			present: []string{"http.request.bytes", "fields.app.name"},
+			missing: []string{"http.request.method"},
+		},
+		"glob": {
+			opts:    []Option{FilterFields(FieldFilter{Exclude: []string{"fields.a*"}})},
+			present: []string{"fields.id", "fields.name"},
+			missing: []string{"fields.app"},
+		},
+		"custom namespace": {
+			opts: []Option{
+				FilterFields(FieldFilter{Exclude: []string{"app_fields.app"}}),
+				UserFieldsNamespace("app_fields"),
+			},
+			present: []string{"app_fields.id", "app_fields.name"},
+			missing: []string{"app_fields.app", "fields"},
+		},
+		"root namespace": {
+			opts: []Option{
+				UserFieldsNamespace(""),
+				FilterFields(FieldFilter{Include: []string{"app", "host"}}),
+			},
+			present: []string{"app.name", "host.hostname"},
+			missing: []string{"id", "name", "http", "fields"},
+		},
+		"labels": {
+			opts: []Option{
+				Labels(),
+				FilterFields(FieldFilter{Exclude: []string{"labels"}}),
+			},
+			present: []string{"fields.id", "fields.app.name"},
+			missing: []string{"labels", "fields.name"},
+		},
+		"error context": {
+			opts:    []Option{FilterFields(FieldFilter{Exclude: []string{"fields.secret"}})},
+			present: []string{"error.ctx.fields.code"},
+			missing: []string{"error.ctx.fields.secret"},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("hello",
+				"id", 42,
+				"name", "test",
+				"app.name", "svc",
+				ecs.HTTP.Request.Method("GET"),
+				ecs.HTTP.Request.Bytes(10),
+				ecs.Host.Hostname("h1"),
+			)
+			msg.Causes = []error{sderr.With("secret", "s3cr3t").With("code", 1).Errf("failed")}
+
+			event := logJSON(t, JSON(nil, test.opts...), msg)
+			for _, key := range test.present {
+				if _, ok := lookup(event, key); !ok {
+					t.Errorf("missing %v in %v", key, event)
+				}
+			}
+			for _, key := range test.missing {
+				if v, ok := lookup(event, key); ok {
+					t.Errorf("unexpected %v=%v", key, v)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterFieldsText(t *testing.T) {
+	msg := testMessage("hello", "id", 42, "secret", "s3cr3t", ecs.Host.Hostname("h1"))
+	out := logString(t, Text(true, FilterFields(FieldFilter{Exclude: []string{"fields.secret"}})), msg)
+	if strings.Contains(out, "s3cr3t") || !strings.Contains(out, "id=42") || !strings.Contains(out, `host.hostname="h1"`) {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestFilterFieldsInvalidPattern(t *testing.T) {
+	_, err := JSON(nil, FilterFields(FieldFilter{Include: []string{"a.[b"}}))(nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestFieldFilterNoCopy(t *testing.T) {
+	f, err := newFieldFilter(FieldFilter{Exclude: []string{"fields.secret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := diag.NewContext(nil, nil)
+	ctx.AddAll("id", 42, "nested", map[string]int{"a": 1}, ecs.Host.Hostname("h1"))
+	if got := f.context(ctx); got != ctx {
+		t.Error("context copied, although no field is removed")
+	}
+
+	ctx.AddAll("secret", "x")
+	if got := f.context(ctx); got == ctx || got.Len() != 3 {
+		t.Errorf("expected filtered context with 3 fields, got %v fields", got.Len())
+	}
+
+	v := diag.ValString("x")
+	if n := testing.AllocsPerRun(100, func() { f.keep("some.nested.key", v, false) }); n != 0 {
+		t.Errorf("matching allocates %v times", n)
+	}
+}
+
+func TestMatchPrefix(t *testing.T) {
+	cases := []struct {
+		pattern string
+		ns      []string
+		key     string
+		match   bool
+	}{
+		{"a", nil, "a", true},
+		{"a", nil, "a.b.c", true},
+		{"a.b", nil, "a", false},
+		{"a.b", nil, "a.b.c", true},
+		{"a.c", nil, "a.b.c", false},
+		{"*.b", nil, "a.b", true},
+		{"a*", nil, "ab.c", true},
+		{"**", nil, "a.b", true},
+		{"**.c", nil, "a.b.c", true},
+		{"**.b", nil, "a.b.c", true},
+		{"**.d", nil, "a.b.c", false},
+		{"a.**.c", nil, "a.c", true},
+		{"a.**.c", nil, "a.x.y.c", true},
+		{"fields", []string{"fields"}, "a.b", true},
+		{"fields.a", []string{"fields"}, "a.b", true},
+		{"fields.b", []string{"fields"}, "a.b", false},
+		{"a.b", []string{"a"}, "b", true},
+		{"a", []string{"x", "y"}, "a", false},
+		{"x.y.a", []string{"x", "y"}, "a", true},
+		{"**.a", []string{"x", "y"}, "a", true},
+		{"x.**", []string{"x", "y"}, "a", true},
+	}
+
+	for _, test := range cases {
+		if got := matchPrefix(strings.Split(test.pattern, "."), test.ns, test.key); got != test.match {
+			t.Errorf("pattern %v on %v.%v: got %v, want %v", test.pattern, test.ns, test.key, got, test.match)
+		}
+	}
+}
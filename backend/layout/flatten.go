@@ -0,0 +1,219 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	structform "github.com/elastic/go-structform"
+)
+
+// flattenVisitor wraps a structform.Visitor, merging nested objects into
+// their parent object. The keys of the nested fields are joined with a
+// separator. Arrays are passed through as is, with objects in arrays being
+// flattened relative to the array element.
+type flattenVisitor struct {
+	out   structform.Visitor
+	sep   string
+	key   string // fully qualified key of the next value
+	stack []flattenFrame
+}
+
+type flattenFrame struct {
+	prefix string // key prefix of the fields in the object
+	array  bool
+	merged bool // object is merged into the parent object
+}
+
+// Flatten configures the structured layout to report nested objects as
+// fields with fully qualified keys, like `{"http.request.method": "GET"}`.
+// The keys are joined with sep, which defaults to "." if empty. Arrays, like
+// the `error.causes` of multi-errors, are kept. Objects in arrays are
+// flattened relative to the array element.
+func Flatten(sep string) Option {
+	return func(o *options) error {
+		if sep == "" {
+			sep = "."
+		}
+		o.flattenSep = sep
+		return nil
+	}
+}
+
+func newFlattenVisitor(out structform.Visitor, sep string) *flattenVisitor {
+	return &flattenVisitor{out: out, sep: sep}
+}
+
+func (v *flattenVisitor) top() *flattenFrame {
+	if len(v.stack) == 0 {
+		return nil
+	}
+	return &v.stack[len(v.stack)-1]
+}
+
+// inObject checks if the next value is a field in an object. Values in
+// arrays and the document itself are not fields.
+func (v *flattenVisitor) inObject() bool {
+	top := v.top()
+	return top != nil && !top.array
+}
+
+func (v *flattenVisitor) OnObjectStart(len int, baseType structform.BaseType) error {
+	if v.inObject() {
+		v.stack = append(v.stack, flattenFrame{prefix: v.key + v.sep, merged: true})
+		return nil
+	}
+
+	v.stack = append(v.stack, flattenFrame{})
+	return v.out.OnObjectStart(len, baseType)
+}
+
+func (v *flattenVisitor) OnObjectFinished() error {
+	frame := v.stack[len(v.stack)-1]
+	v.stack = v.stack[:len(v.stack)-1]
+	if frame.merged {
+		return nil
+	}
+	return v.out.OnObjectFinished()
+}
+
+func (v *flattenVisitor) OnKey(key string) error {
+	v.key = v.top().prefix + key
+	return nil
+}
+
+func (v *flattenVisitor) OnArrayStart(len int, baseType structform.BaseType) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	v.stack = append(v.stack, flattenFrame{array: true})
+	return v.out.OnArrayStart(len, baseType)
+}
+
+func (v *flattenVisitor) OnArrayFinished() error {
+	v.stack = v.stack[:len(v.stack)-1]
+	return v.out.OnArrayFinished()
+}
+
+// onValue writes the fully qualified key, if the value is a field in an
+// object.
+func (v *flattenVisitor) onValue() error {
+	if !v.inObject() {
+		return nil
+	}
+	return v.out.OnKey(v.key)
+}
+
+func (v *flattenVisitor) OnNil() error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnNil()
+}
+
+func (v *flattenVisitor) OnBool(b bool) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnBool(b)
+}
+
+func (v *flattenVisitor) OnString(s string) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnString(s)
+}
+
+func (v *flattenVisitor) OnInt8(i int8) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnInt8(i)
+}
+
+func (v *flattenVisitor) OnInt16(i int16) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnInt16(i)
+}
+
+func (v *flattenVisitor) OnInt32(i int32) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnInt32(i)
+}
+
+func (v *flattenVisitor) OnInt64(i int64) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnInt64(i)
+}
+
+func (v *flattenVisitor) OnInt(i int) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnInt(i)
+}
+
+func (v *flattenVisitor) OnByte(b byte) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnByte(b)
+}
+
+func (v *flattenVisitor) OnUint8(u uint8) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnUint8(u)
+}
+
+func (v *flattenVisitor) OnUint16(u uint16) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnUint16(u)
+}
+
+func (v *flattenVisitor) OnUint32(u uint32) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnUint32(u)
+}
+
+func (v *flattenVisitor) OnUint64(u uint64) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnUint64(u)
+}
+
+func (v *flattenVisitor) OnUint(u uint) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnUint(u)
+}
+
+func (v *flattenVisitor) OnFloat32(f float32) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnFloat32(f)
+}
+
+func (v *flattenVisitor) OnFloat64(f float64) error {
+	if err := v.onValue(); err != nil {
+		return err
+	}
+	return v.out.OnFloat64(f)
+}
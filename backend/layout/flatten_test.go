@@ -0,0 +1,92 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/urso/diag-ecs/ecs"
+	"github.com/urso/sderr"
+)
+
+func TestFlatten(t *testing.T) {
+	cases := map[string]struct {
+		sep  string
+		want map[string]interface{}
+	}{
+		"default separator": {
+			sep: "",
+			want: map[string]interface{}{
+				"message":             "hello",
+				"log.level":           "info",
+				"http.request.method": "GET",
+				"fields.id":           float64(42),
+				"fields.m.a.b":        float64(1),
+				"error.message":       "outer",
+			},
+		},
+		"custom separator": {
+			sep: "_",
+			want: map[string]interface{}{
+				"message":             "hello",
+				"log_level":           "info",
+				"http_request_method": "GET",
+				"fields_id":           float64(42),
+				"fields_m_a_b":        float64(1),
+				"error_message":       "outer",
+			},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("hello",
+				"id", 42,
+				ecs.HTTP.Request.Method("GET"),
+				"tags", []string{"a", "b"},
+				"m", map[string]interface{}{"a": map[string]int{"b": 1}},
+			)
+			msg.Causes = []error{sderr.WrapAll([]error{
+				sderr.With("k", 1).Errf("first"),
+				errors.New("second"),
+			}, "outer")}
+
+			event := logJSON(t, JSON(nil, Flatten(test.sep)), msg)
+			for key, want := range test.want {
+				if got, ok := event[key]; !ok || got != want {
+					t.Errorf("%v: got %v, want %v", key, got, want)
+				}
+			}
+
+			sep := test.sep
+			if sep == "" {
+				sep = "."
+			}
+			for key, value := range event {
+				if _, ok := value.(map[string]interface{}); ok {
+					t.Errorf("%v: unexpected nested object", key)
+				}
+			}
+
+			// arrays are kept, and objects in arrays are flattened relative
+			// to the array element.
+			if tags, ok := event["fields"+sep+"tags"].([]interface{}); !ok || len(tags) != 2 {
+				t.Errorf("expected tags array, got %v", event["fields"+sep+"tags"])
+			}
+			causes, ok := event["error"+sep+"causes"].([]interface{})
+			if !ok || len(causes) != 2 {
+				t.Fatalf("expected 2 causes, got %v", event["error"+sep+"causes"])
+			}
+			first, _ := causes[0].(map[string]interface{})
+			if first["message"] != "first" || first["ctx"+sep+"fields"+sep+"k"] != float64(1) {
+				t.Errorf("unexpected cause: %v", first)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// FrameReader splits a stream written by a structured layout into events.
+// Corrupted frames, like events cut short by a partial write, are skipped
+// and the reader resynchronizes on the next valid frame.
+//
+// Without validation the reader only detects malformed frame headers.
+// Validating the events, via ValidJSON or ValidCBOR, is required to detect
+// frames containing partial events. The framings have no synchronization
+// markers, such that objects nested in a partial event may pass validation.
+// Use a stricter validation, like checking for the `message` field, if this
+// is not acceptable.
+type FrameReader struct {
+	r       io.Reader
+	framing Framing
+	valid   func([]byte) bool
+
+	buf     []byte // buffered input, buf[pos:] has not been read yet
+	pos     int
+	eof     bool
+	err     error
+	skipped int64
+
+	// MaxFrameSize limits the size of events. Larger frames are considered
+	// corrupted. Defaults to DefaultMaxFrameSize.
+	MaxFrameSize int
+}
+
+// DefaultMaxFrameSize is the default limit of the event size of a FrameReader.
+const DefaultMaxFrameSize = 16 << 20
+
+// frameReaderBufferSize is the minimum number of bytes read at once.
+const frameReaderBufferSize = 32 << 10
+
+// NewFrameReader creates a reader for events written with framing f. All
+// events are checked by valid if valid is not nil. Events failing
+// validation are skipped. FrameNone is not supported.
+func NewFrameReader(r io.Reader, f Framing, valid func([]byte) bool) *FrameReader {
+	return &FrameReader{r: r, framing: f, valid: valid, MaxFrameSize: DefaultMaxFrameSize}
+}
+
+// Next returns the next event. The event is only valid until the next call
+// to Next. io.EOF is returned if all input has been read.
+func (r *FrameReader) Next() ([]byte, error) {
+	for {
+		avail := r.buf[r.pos:]
+		if len(avail) == 0 && r.eof {
+			if r.err != nil {
+				return nil, r.err
+			}
+			return nil, io.EOF
+		}
+
+		event, n, err := r.framing.parseFrame(avail, r.MaxFrameSize, r.eof)
+		switch err {
+		case nil:
+			if len(event) == 0 {
+				r.pos += n // ignore empty lines
+				continue
+			}
+			if r.valid != nil && !r.valid(event) {
+				// The frame might start within a partial event. Resynchronize
+				// byte by byte, so to not miss the start of the next frame.
+				r.skip(1)
+				continue
+			}
+			r.pos += n
+			return event, nil
+
+		case errShortFrame:
+			if r.eof {
+				// the stream ends with a partial frame
+				r.skip(1)
+			} else {
+				r.fill()
+			}
+
+		case errInvalidFrame:
+			r.skip(n)
+
+		default:
+			return nil, err
+		}
+	}
+}
+
+// Skipped reports the number of bytes skipped due to corrupted frames.
+func (r *FrameReader) Skipped() int64 {
+	return r.skipped
+}
+
+func (r *FrameReader) skip(n int) {
+	r.pos += n
+	r.skipped += int64(n)
+}
+
+// fill reads more input into the buffer. Consumed input is discarded first.
+func (r *FrameReader) fill() {
+	if r.pos > 0 {
+		n := copy(r.buf, r.buf[r.pos:])
+		r.buf = r.buf[:n]
+		r.pos = 0
+	}
+
+	if cap(r.buf)-len(r.buf) < frameReaderBufferSize {
+		tmp := make([]byte, len(r.buf), 2*cap(r.buf)+frameReaderBufferSize)
+		copy(tmp, r.buf)
+		r.buf = tmp
+	}
+
+	n, err := r.r.Read(r.buf[len(r.buf):cap(r.buf)])
+	r.buf = r.buf[:len(r.buf)+n]
+	if err != nil {
+		r.eof = true
+		if err != io.EOF {
+			r.err = err
+		}
+	}
+}
+
+// ValidJSON checks if event is a JSON object. It can be used to validate the
+// events read by a FrameReader.
+func ValidJSON(event []byte) bool {
+	return len(event) >= 2 && event[0] == '{' && event[len(event)-1] == '}' && json.Valid(event)
+}
+
+// ValidCBOR checks if event is a single CBOR encoded map. It can be used to
+// validate the events read by a FrameReader.
+func ValidCBOR(event []byte) bool {
+	if len(event) == 0 || event[0]>>5 != 5 {
+		return false
+	}
+	n, err := cborItemLen(event, len(event))
+	return err == nil && n == len(event)
+}
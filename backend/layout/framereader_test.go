@@ -0,0 +1,97 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+)
+
+func TestFrameReader(t *testing.T) {
+	cases := []struct {
+		name    string
+		factory Factory
+		framing Framing
+		valid   func([]byte) bool
+	}{
+		{"json newline", JSON(nil), FrameNewline, ValidJSON},
+		{"json varint", JSON(nil, Framed(FrameVarint)), FrameVarint, ValidJSON},
+		{"json uint32", JSON(nil, Framed(FrameUint32)), FrameUint32, ValidJSON},
+		{"cbor sequence", CBOR(nil), FrameCBORSequence, validCBOREvent},
+		{"cbor varint", CBOR(nil, Framed(FrameVarint)), FrameVarint, validCBOREvent},
+	}
+
+	for _, test := range cases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var events [][]byte
+			var stream bytes.Buffer
+			for i, msg := range []string{"first", "second", "third"} {
+				var out bytes.Buffer
+				l, err := test.factory(&out)
+				if err != nil {
+					t.Fatal(err)
+				}
+				l.Log(testFrameMessage(msg))
+				events = append(events, out.Bytes())
+
+				if i == 1 {
+					// partial write of an event, cut in the middle
+					stream.Write(out.Bytes()[:out.Len()/2])
+				}
+				stream.Write(out.Bytes())
+			}
+
+			// writes with partial frames at the end are dropped
+			stream.Write(events[0][:len(events[0])/2])
+
+			r := NewFrameReader(iotest.OneByteReader(&stream), test.framing, test.valid)
+			for i, frame := range events {
+				event, err := r.Next()
+				if err != nil {
+					t.Fatalf("failed to read event %v: %v", i, err)
+				}
+
+				if got := test.framing.AppendFrame(nil, event); !bytes.Equal(got, frame) {
+					t.Errorf("event %v does not match:\n got: %q\nwant: %q", i, got, frame)
+				}
+			}
+
+			if _, err := r.Next(); err != io.EOF {
+				t.Errorf("expected EOF, got %v", err)
+			}
+			if r.Skipped() == 0 {
+				t.Error("expected corrupted frames to be skipped")
+			}
+		})
+	}
+}
+
+func TestFrameReaderNone(t *testing.T) {
+	r := NewFrameReader(bytes.NewReader([]byte("{}")), FrameNone, nil)
+	if _, err := r.Next(); err == nil || err == io.EOF {
+		t.Errorf("expected error for unsupported framing, got %v", err)
+	}
+}
+
+// validCBOREvent requires the message field, such that objects nested in
+// the partial event are not accepted after resynchronizing.
+func validCBOREvent(event []byte) bool {
+	return ValidCBOR(event) && bytes.Contains(event, []byte("gmessage"))
+}
+
+func testFrameMessage(msg string) backend.Message {
+	ctx := diag.NewContext(nil, nil)
+	ctx.AddAll("count", 42, "tags", []string{"a", "b"})
+	return backend.Message{Name: "test", Level: backend.Info, Message: msg, Context: ctx}
+}
@@ -0,0 +1,315 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Framing delimits the events written by the structured layouts, such that
+// readers can split a stream or file into events. Each event is written
+// with a single Write call, including the frame.
+type Framing uint8
+
+const (
+	// FrameNone writes events back to back, without any delimiter.
+	FrameNone Framing = iota
+
+	// FrameNewline terminates each event with a newline. It must only be
+	// used with encodings that never contain newlines, like JSON.
+	FrameNewline
+
+	// FrameVarint prefixes each event with its length, encoded as unsigned
+	// varint like in encoding/binary.
+	FrameVarint
+
+	// FrameUint32 prefixes each event with its length, encoded as 4 byte big
+	// endian integer.
+	FrameUint32
+
+	// FrameCBORSequence writes CBOR events back to back, forming a CBOR
+	// sequence as defined in RFC 8742. Readers split the stream by decoding
+	// the length of each CBOR data item.
+	FrameCBORSequence
+)
+
+// maximum frame buffer size to keep in between calls
+const persistentFrameBufferSize = 4096
+
+var (
+	// errShortFrame is reported if the input ends before the frame is
+	// complete.
+	errShortFrame = errors.New("incomplete frame")
+
+	// errInvalidFrame is reported if the frame header or the CBOR data item
+	// is malformed.
+	errInvalidFrame = errors.New("invalid frame")
+)
+
+// Framed configures the framing used by the structured layouts. The JSON and
+// Logfmt layouts, and layouts created via Structured default to
+// FrameNewline. UBJSON defaults to FrameNone, and CBOR to
+// FrameCBORSequence. Text layouts always terminate events with a newline,
+// the Pattern layout only if the pattern ends with %n. Appenders write events
+// as is, without adding delimiters.
+func Framed(f Framing) Option {
+	return func(o *options) error {
+		if f > FrameCBORSequence {
+			return fmt.Errorf("unknown framing %v", f)
+		}
+		o.framing = f
+		return nil
+	}
+}
+
+func (f Framing) String() string {
+	switch f {
+	case FrameNone:
+		return "none"
+	case FrameNewline:
+		return "newline"
+	case FrameVarint:
+		return "varint"
+	case FrameUint32:
+		return "uint32"
+	case FrameCBORSequence:
+		return "cbor-sequence"
+	default:
+		return fmt.Sprintf("<framing %d>", uint8(f))
+	}
+}
+
+// AppendFrame appends the framed event to dst and returns the extended
+// buffer.
+func (f Framing) AppendFrame(dst, event []byte) []byte {
+	switch f {
+	case FrameNewline:
+		dst = append(dst, event...)
+		return append(dst, '\n')
+	case FrameVarint:
+		var hdr [binary.MaxVarintLen64]byte
+		n := binary.PutUvarint(hdr[:], uint64(len(event)))
+		dst = append(dst, hdr[:n]...)
+		return append(dst, event...)
+	case FrameUint32:
+		var hdr [4]byte
+		binary.BigEndian.PutUint32(hdr[:], uint32(len(event)))
+		dst = append(dst, hdr[:]...)
+		return append(dst, event...)
+	default:
+		return append(dst, event...)
+	}
+}
+
+// parseFrame splits the next frame from b. It returns the event and the
+// number of bytes consumed. If the frame is invalid, errInvalidFrame is
+// returned with the number of bytes to skip. Frames with events larger than
+// max are invalid. If eof is set, b holds all remaining input.
+func (f Framing) parseFrame(b []byte, max int, eof bool) ([]byte, int, error) {
+	switch f {
+	case FrameNewline:
+		for i, c := range b {
+			if c == '\n' {
+				if i > max {
+					return nil, i + 1, errInvalidFrame
+				}
+				return b[:i], i + 1, nil
+			}
+		}
+		if len(b) > max {
+			return nil, len(b), errInvalidFrame
+		}
+		if eof {
+			return b, len(b), nil
+		}
+		return nil, 0, errShortFrame
+
+	case FrameVarint, FrameUint32:
+		var size uint64
+		var hdr int
+		if f == FrameVarint {
+			size, hdr = binary.Uvarint(b)
+			if hdr < 0 {
+				return nil, 1, errInvalidFrame
+			}
+			if hdr == 0 {
+				if len(b) >= binary.MaxVarintLen64 {
+					return nil, 1, errInvalidFrame
+				}
+				return nil, 0, errShortFrame
+			}
+		} else {
+			if len(b) < 4 {
+				return nil, 0, errShortFrame
+			}
+			size, hdr = uint64(binary.BigEndian.Uint32(b)), 4
+		}
+
+		if size == 0 || size > uint64(max) {
+			return nil, 1, errInvalidFrame
+		}
+		end := hdr + int(size)
+		if end > len(b) {
+			return nil, 0, errShortFrame
+		}
+		return b[hdr:end], end, nil
+
+	case FrameCBORSequence:
+		n, err := cborItemLen(b, max)
+		switch err {
+		case nil:
+			return b[:n], n, nil
+		case errShortFrame:
+			return nil, 0, err
+		default:
+			return nil, 1, errInvalidFrame
+		}
+
+	default:
+		return nil, 0, fmt.Errorf("framing %v can not be read", f)
+	}
+}
+
+// maxCBORNesting limits the nesting of arrays, maps, and tags when splitting
+// CBOR sequences.
+const maxCBORNesting = 256
+
+// cborItemLen returns the length of the CBOR data item at the start of b. It
+// returns errShortFrame if b ends within the item, and errInvalidFrame if
+// the item is malformed or larger than max.
+func cborItemLen(b []byte, max int) (int, error) {
+	if len(b) > max {
+		b = b[:max]
+		n, err := cborItemEnd(b, 0, 0)
+		if err == errShortFrame {
+			return 0, errInvalidFrame
+		}
+		return n, err
+	}
+	return cborItemEnd(b, 0, 0)
+}
+
+// cborItemEnd returns the offset of the end of the data item starting at
+// off.
+func cborItemEnd(b []byte, off, depth int) (int, error) {
+	if depth > maxCBORNesting {
+		return 0, errInvalidFrame
+	}
+	if off >= len(b) {
+		return 0, errShortFrame
+	}
+
+	major, info := b[off]>>5, b[off]&0x1f
+	off++
+
+	var arg uint64
+	indefinite := false
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		n := 1 << (info - 24)
+		if off+n > len(b) {
+			return 0, errShortFrame
+		}
+		for _, c := range b[off : off+n] {
+			arg = arg<<8 | uint64(c)
+		}
+		off += n
+	case info == 31:
+		indefinite = true
+	default:
+		return 0, errInvalidFrame
+	}
+
+	switch major {
+	case 0, 1: // integers
+		if indefinite {
+			return 0, errInvalidFrame
+		}
+		return off, nil
+
+	case 2, 3: // byte and text strings
+		if !indefinite {
+			if arg > uint64(len(b)-off) {
+				return 0, errShortFrame
+			}
+			return off + int(arg), nil
+		}
+
+		// indefinite strings are sequences of definite strings of the same
+		// major type, terminated by a break.
+		for {
+			if off >= len(b) {
+				return 0, errShortFrame
+			}
+			if b[off] == 0xff {
+				return off + 1, nil
+			}
+			if b[off]>>5 != major || b[off]&0x1f == 31 {
+				return 0, errInvalidFrame
+			}
+			end, err := cborItemEnd(b, off, depth+1)
+			if err != nil {
+				return 0, err
+			}
+			off = end
+		}
+
+	case 4, 5: // arrays and maps
+		items := arg
+		if major == 5 {
+			items *= 2
+		}
+		if !indefinite {
+			if items > uint64(len(b)-off) {
+				// each item requires at least one byte
+				return 0, errShortFrame
+			}
+			var err error
+			for i := uint64(0); i < items; i++ {
+				if off, err = cborItemEnd(b, off, depth+1); err != nil {
+					return 0, err
+				}
+			}
+			return off, nil
+		}
+
+		count := 0
+		for {
+			if off >= len(b) {
+				return 0, errShortFrame
+			}
+			if b[off] == 0xff {
+				if major == 5 && count%2 != 0 {
+					return 0, errInvalidFrame
+				}
+				return off + 1, nil
+			}
+			end, err := cborItemEnd(b, off, depth+1)
+			if err != nil {
+				return 0, err
+			}
+			off = end
+			count++
+		}
+
+	case 6: // tags
+		if indefinite {
+			return 0, errInvalidFrame
+		}
+		return cborItemEnd(b, off, depth+1)
+
+	default: // simple values and floats
+		if indefinite {
+			return 0, errInvalidFrame // break outside of indefinite item
+		}
+		return off, nil
+	}
+}
@@ -0,0 +1,80 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/json"
+)
+
+func TestFramingDefaults(t *testing.T) {
+	structured := Structured(func(w io.Writer) structform.Visitor {
+		return json.NewVisitor(w)
+	}, nil)
+
+	cases := map[string]struct {
+		factory Factory
+		check   func(out string) bool
+	}{
+		"json":       {JSON(nil), newlineFramed},
+		"structured": {structured, newlineFramed},
+		"logfmt":     {Logfmt(nil), newlineFramed},
+		"ubjson": {UBJSON(nil), func(out string) bool {
+			return strings.HasPrefix(out, "{") && strings.HasSuffix(out, "}")
+		}},
+		"ubjson uint32": {UBJSON(nil, Framed(FrameUint32)), func(out string) bool {
+			return len(out) > 4 && int(binary.BigEndian.Uint32([]byte(out))) == len(out)-4
+		}},
+		"logfmt varint": {Logfmt(nil, Framed(FrameVarint)), func(out string) bool {
+			size, n := binary.Uvarint([]byte(out))
+			return n > 0 && int(size) == len(out)-n && !strings.Contains(out, "\n")
+		}},
+		"structured none": {Structured(func(w io.Writer) structform.Visitor {
+			return json.NewVisitor(w)
+		}, nil, Framed(FrameNone)), func(out string) bool {
+			return strings.HasPrefix(out, "{") && strings.HasSuffix(out, "}")
+		}},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			if out := logString(t, test.factory, testMessage("hello")); !test.check(out) {
+				t.Errorf("unexpected framing: %q", out)
+			}
+		})
+	}
+}
+
+// newlineFramed checks that out is a single line terminated by a newline.
+func newlineFramed(out string) bool {
+	return strings.Count(out, "\n") == 1 && strings.HasSuffix(out, "\n")
+}
+
+func TestFrameBufferBounded(t *testing.T) {
+	var out bytes.Buffer
+	l, err := JSON(nil, Framed(FrameUint32))(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Log(testMessage(strings.Repeat("x", 2*persistentFrameBufferSize)))
+	if n := cap(l.(*structLayout).frame); n > persistentFrameBufferSize {
+		t.Errorf("frame buffer of %v bytes kept", n)
+	}
+
+	l.Log(testMessage("hello"))
+	if n := cap(l.(*structLayout).frame); n == 0 {
+		t.Error("small frame buffer not kept")
+	}
+}
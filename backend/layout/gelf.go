@@ -0,0 +1,234 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/json"
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+)
+
+type gelfLayout struct {
+	out      io.Writer
+	buf      bytes.Buffer
+	enc      *json.Visitor
+	host     string
+	fields   *diag.Context
+	redact   *redactor
+	filter   *fieldFilter
+	encoders valueEncoders
+	limits   Limits
+	errors   *textLayout // renders the error tree into full_message
+}
+
+// gelfReserved lists the additional fields set by the layout. Context fields
+// using the same names are ignored.
+var gelfReserved = map[string]bool{
+	"id":     true, // forbidden by the GELF spec
+	"logger": true,
+	"file":   true,
+	"line":   true,
+}
+
+// GELF creates a layout writing messages in the Graylog Extended Log Format
+// (GELF) version 1.1. Each message is written with a single Write call and
+// without any framing, so the appender can frame, compress, or chunk it as
+// required by the transport.
+//
+// Errors are reported as tree in `full_message`. Context fields are flattened
+// into additional fields with dotted names, like `_host.hostname`. The host
+// defaults to the hostname reported by the operating system if empty.
+func GELF(host string, fields []diag.Field, opts ...Option) Factory {
+	return func(out io.Writer) (Layout, error) {
+		o, err := applyOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if host == "" {
+			if host, err = os.Hostname(); err != nil {
+				return nil, err
+			}
+		}
+
+		logCtx := diag.NewContext(nil, nil)
+		logCtx.AddFields(fields...)
+
+		l := &gelfLayout{
+			out:      out,
+			host:     host,
+			fields:   logCtx,
+			redact:   o.redact,
+			filter:   o.filter,
+			encoders: o.encoders,
+			limits:   o.limits,
+			errors:   &textLayout{errCtx: o.errCtx, redact: o.redact, filter: o.filter, encoders: o.encoders, limits: o.limits},
+		}
+		l.enc = json.NewVisitor(&l.buf)
+		return l, nil
+	}
+}
+
+func (l *gelfLayout) UseContext() bool { return true }
+
+func (l *gelfLayout) Log(msg backend.Message) {
+	defer l.buf.Reset()
+
+	ts := eventTime(msg)
+	message := l.redact.string(msg.Message)
+
+	fullMessage := ""
+	l.errors.buf.Reset()
+	if l.errors.writeErrors(msg.Causes, "\t") == nil && l.errors.buf.Len() > 0 {
+		fullMessage = message + "\n" + l.errors.buf.String()
+	}
+
+	enc := l.enc
+	enc.OnObjectStart(-1, structform.AnyType)
+	l.writeString("version", "1.1")
+	l.writeString("host", l.host)
+	l.writeString("short_message", message)
+	if fullMessage != "" {
+		l.writeString("full_message", fullMessage)
+	}
+	enc.OnKey("timestamp")
+	enc.OnFloat64(float64(ts.UnixNano()/int64(time.Millisecond)) / 1000)
+	enc.OnKey("level")
+	enc.OnInt(gelfLevel(msg.Level))
+
+	if msg.Name != "" {
+		l.writeString("_logger", msg.Name)
+	}
+	l.writeString("_file", msg.Caller.File())
+	enc.OnKey("_line")
+	enc.OnInt(msg.Caller.Line())
+
+	ctx := l.filter.context(l.redact.context(msg.Context))
+	if l.fields.Len() > 0 {
+		ctx = diag.NewContext(l.fields, ctx)
+	}
+	err := visitFlatValues(ctx, "", l.writeField)
+	if err == nil {
+		err = enc.OnObjectFinished()
+	}
+	if err != nil {
+		// reset encoder state
+		l.enc = json.NewVisitor(&l.buf)
+		return
+	}
+	l.out.Write(l.buf.Bytes())
+}
+
+func (l *gelfLayout) writeString(key, value string) {
+	l.enc.OnKey(key)
+	l.enc.OnString(value)
+}
+
+// gelfLevel maps log levels to syslog severities.
+func gelfLevel(lvl backend.Level) int {
+	switch lvl {
+	case backend.Trace, backend.Debug:
+		return 7
+	case backend.Info:
+		return 6
+	case backend.Error:
+		return 3
+	default:
+		return 5
+	}
+}
+
+func (l *gelfLayout) writeField(key string, value interface{}) error {
+	if gelfReserved[key] {
+		return nil
+	}
+
+	enc := l.enc
+	switch val := l.encoders.encode(value).(type) {
+	case nil:
+		// GELF has no null values
+		return nil
+	case string:
+		enc.OnKey(gelfKey(key))
+		return enc.OnString(val)
+	case int:
+		enc.OnKey(gelfKey(key))
+		return enc.OnInt(val)
+	case int8, int16, int32, int64:
+		enc.OnKey(gelfKey(key))
+		return enc.OnInt64(toInt64(val))
+	case uint, uint8, uint16, uint32, uint64:
+		enc.OnKey(gelfKey(key))
+		return enc.OnUint64(toUint64(val))
+	case float32:
+		enc.OnKey(gelfKey(key))
+		return enc.OnFloat32(val)
+	case float64:
+		enc.OnKey(gelfKey(key))
+		return enc.OnFloat64(val)
+	default:
+		// GELF only supports strings and numbers
+		str, err := l.encoders.encodeString(value, l.limits)
+		if err != nil {
+			return err
+		}
+		enc.OnKey(gelfKey(key))
+		return enc.OnString(l.redact.string(str))
+	}
+}
+
+// gelfKey creates the name of an additional field. Additional fields are
+// prefixed with an underscore and must only contain letters, numbers,
+// underscores, dashes, and dots.
+func gelfKey(key string) string {
+	return "_" + strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		case r == '_', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+func toInt64(v interface{}) int64 {
+	switch i := v.(type) {
+	case int8:
+		return int64(i)
+	case int16:
+		return int64(i)
+	case int32:
+		return int64(i)
+	default:
+		return v.(int64)
+	}
+}
+
+func toUint64(v interface{}) uint64 {
+	switch u := v.(type) {
+	case uint:
+		return uint64(u)
+	case uint8:
+		return uint64(u)
+	case uint16:
+		return uint64(u)
+	case uint32:
+		return uint64(u)
+	default:
+		return v.(uint64)
+	}
+}
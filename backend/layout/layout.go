@@ -8,6 +8,9 @@ package layout
 
 import (
 	"io"
+	"time"
+
+	"github.com/elastic/go-structform/gotype"
 
 	"github.com/urso/ecslog/backend"
 )
@@ -18,3 +21,56 @@ type Layout interface {
 	UseContext() bool
 	Log(msg backend.Message)
 }
+
+// Option configures optional features of the text and structured layouts.
+// Invalid options are reported by the Factory when creating the layout.
+type Option func(*options) error
+
+type options struct {
+	redact      *redactor
+	foldOpts    []gotype.FoldOption
+	rootErrType bool
+	errCtx      bool
+	colors      *ColorScheme
+
+	eventTimestamp bool
+	ecsVersion     string
+	originFunction bool
+	relPaths       bool
+
+	userNamespace *string
+	labels        bool
+	flattenSep    string
+	order         FieldOrder
+	filter        *fieldFilter
+	encoders      valueEncoders
+	limits        Limits
+	rawText       bool
+	framing       Framing
+	bulk          *bulkIndex
+}
+
+func applyOptions(opts []Option) (options, error) {
+	o := options{
+		encoders: valueEncoders{marshalers: defaultMarshalers},
+		limits:   defaultLimits,
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return o, err
+		}
+	}
+	if o.filter != nil {
+		o.filter.setUserFields(newUserFieldsMapping(o))
+	}
+	return o, nil
+}
+
+// eventTime returns the timestamp of the message. The current time is used if
+// the message has no timestamp.
+func eventTime(msg backend.Message) time.Time {
+	if msg.Timestamp.IsZero() {
+		return time.Now()
+	}
+	return msg.Timestamp
+}
@@ -0,0 +1,65 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/urso/diag"
+	"github.com/urso/ecslog/backend"
+)
+
+// testMessage creates a message with the given context.
+func testMessage(msg string, ctx ...interface{}) backend.Message {
+	c := diag.NewContext(nil, nil)
+	c.AddAll(ctx...)
+	return backend.Message{Name: "test", Level: backend.Info, Message: msg, Context: c}
+}
+
+// logString writes msg with a layout created by factory, and returns the
+// output.
+func logString(t *testing.T, factory Factory, msg backend.Message) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	l, err := factory(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Log(msg)
+	return buf.String()
+}
+
+// logJSON writes msg with a JSON layout created by factory, and decodes the
+// event.
+func logJSON(t *testing.T, factory Factory, msg backend.Message) map[string]interface{} {
+	t.Helper()
+
+	out := logString(t, factory, msg)
+	var event map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &event); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	return event
+}
+
+// lookup returns the value of a dotted key in a decoded event.
+func lookup(event map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := event[key]; ok {
+		return v, true
+	}
+	for k, v := range event {
+		if len(key) > len(k) && key[len(k)] == '.' && key[:len(k)] == k {
+			if nested, ok := v.(map[string]interface{}); ok {
+				return lookup(nested, key[len(k)+1:])
+			}
+		}
+	}
+	return nil, false
+}
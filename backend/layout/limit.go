@@ -0,0 +1,394 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// Limits bounds the size of the events created by a layout. Values exceeding
+// a limit are truncated and marked with `…(truncated)`. The structured
+// layouts additionally report `log.truncated: true`.
+//
+// A zero value keeps the default limit, negative values disable the limit.
+type Limits struct {
+	// MaxDepth limits the nesting of objects and arrays within a field
+	// value. Values nested deeper are replaced by the truncation marker.
+	// Self-referencing values are stopped by this limit, and overflow the
+	// stack if the limit is disabled. The structured layouts stop encoding
+	// a field at twice the limit, dropping the remaining contents of the
+	// field. Defaults to 64.
+	MaxDepth int
+
+	// MaxStringLen limits the length of strings in bytes. Unlimited by
+	// default.
+	MaxStringLen int
+
+	// MaxArrayLen limits the number of elements reported for arrays.
+	// Unlimited by default.
+	MaxArrayLen int
+
+	// MaxEventSize limits the size of an encoded event in bytes. The
+	// structured layouts report the event without context and error details
+	// if the event is too large. The text layouts cut the line. Unlimited by
+	// default.
+	MaxEventSize int
+
+	// MaxErrorDepth limits the number of nested causes reported for an
+	// error. Defaults to 32.
+	MaxErrorDepth int
+}
+
+// truncatedMarker replaces or is appended to values that have been
+// truncated.
+const truncatedMarker = "…(truncated)"
+
+var defaultLimits = Limits{
+	MaxDepth:      64,
+	MaxErrorDepth: maxErrorDepth,
+}
+
+var (
+	// errMaxDepth is reported by the limitVisitor if a value is nested
+	// twice as deep as permitted, such that self-referencing values do not
+	// recurse forever.
+	errMaxDepth = errors.New("maximum nesting depth exceeded")
+
+	// errMaxEventSize is reported by the limitVisitor if the encoded event
+	// exceeds the configured size.
+	errMaxEventSize = errors.New("maximum event size exceeded")
+)
+
+// Limit configures the limits of the layout.
+func Limit(l Limits) Option {
+	return func(o *options) error {
+		o.limits = Limits{
+			MaxDepth:      normLimit(l.MaxDepth, defaultLimits.MaxDepth),
+			MaxStringLen:  normLimit(l.MaxStringLen, defaultLimits.MaxStringLen),
+			MaxArrayLen:   normLimit(l.MaxArrayLen, defaultLimits.MaxArrayLen),
+			MaxEventSize:  normLimit(l.MaxEventSize, defaultLimits.MaxEventSize),
+			MaxErrorDepth: normLimit(l.MaxErrorDepth, defaultLimits.MaxErrorDepth),
+		}
+		return nil
+	}
+}
+
+// normLimit converts a configured limit into the internal representation,
+// with 0 disabling the limit.
+func normLimit(v, def int) int {
+	switch {
+	case v == 0:
+		return def
+	case v < 0:
+		return 0
+	default:
+		return v
+	}
+}
+
+// truncateString cuts s to at most max bytes, without splitting a UTF-8
+// sequence, and appends the truncation marker. The string is returned as is
+// if max is 0 or s is short enough.
+func truncateString(s string, max int) (string, bool) {
+	if max <= 0 || len(s) <= max {
+		return s, false
+	}
+
+	end := max
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end] + truncatedMarker, true
+}
+
+// limitVisitor wraps a structform.Visitor, enforcing the limits on the
+// values passed through. Array elements beyond the limit are dropped,
+// including nested objects and arrays. Objects and arrays nested too deep are
+// replaced by the truncation marker, and their contents are dropped, such
+// that encoding continues with their siblings. Values nested twice as deep
+// abort encoding with errMaxDepth, such that recursive values do not recurse
+// forever. The caller is required to restore the visitor state via recover.
+// If the depth is limited, objects and arrays are passed on with unknown
+// length, as recover might close them early.
+type limitVisitor struct {
+	out       structform.Visitor
+	limits    Limits
+	size      func() int // reports the current size of the encoded event
+	stack     []limitFrame
+	truncated bool // at least one value has been truncated
+
+	maxString int // effective string limit
+
+	// fieldsOnly applies the depth limit to field values only, relative to
+	// base, such that the objects of the event itself are never truncated.
+	// base is -1 while no field value is being encoded.
+	fieldsOnly bool
+	base       int
+}
+
+type limitFrame struct {
+	array bool
+	skip  bool // the container is dropped, its contents are ignored
+	n     int  // number of elements in an array
+}
+
+func newLimitVisitor(out structform.Visitor, limits Limits, size func() int) *limitVisitor {
+	return &limitVisitor{out: out, limits: limits, size: size, maxString: limits.MaxStringLen}
+}
+
+// reset prepares the visitor for the next event.
+func (v *limitVisitor) reset() {
+	v.stack = v.stack[:0]
+	v.truncated = false
+	v.maxString = v.limits.MaxStringLen
+	if v.fieldsOnly {
+		v.base = -1
+	}
+}
+
+// enterField starts applying the depth limit relative to the current depth,
+// unless a field value is already being encoded.
+func (v *limitVisitor) enterField() bool {
+	if !v.fieldsOnly || v.base >= 0 {
+		return false
+	}
+	v.base = len(v.stack)
+	return true
+}
+
+func (v *limitVisitor) leaveField() { v.base = -1 }
+
+func (v *limitVisitor) depth() int { return len(v.stack) }
+
+func (v *limitVisitor) skipping() bool {
+	return len(v.stack) > 0 && v.stack[len(v.stack)-1].skip
+}
+
+// recover closes all objects and arrays opened after depth, after encoding
+// has been aborted with errMaxDepth.
+func (v *limitVisitor) recover(depth int) error {
+	v.truncated = true
+	for len(v.stack) > depth {
+		frame := v.stack[len(v.stack)-1]
+		v.stack = v.stack[:len(v.stack)-1]
+		if frame.skip {
+			continue
+		}
+
+		var err error
+		if frame.array {
+			err = v.out.OnArrayFinished()
+		} else {
+			err = v.out.OnObjectFinished()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// begin checks the limits for the next value. It returns false if the
+// value must not be passed to the wrapped visitor.
+func (v *limitVisitor) begin(container, array bool) (bool, error) {
+	if v.limits.MaxEventSize > 0 && v.size() > v.limits.MaxEventSize {
+		return false, errMaxEventSize
+	}
+
+	skip := v.skipping()
+	if !skip && len(v.stack) > 0 {
+		top := &v.stack[len(v.stack)-1]
+		if top.array {
+			top.n++
+			if v.limits.MaxArrayLen > 0 && top.n > v.limits.MaxArrayLen {
+				v.truncated = true
+				skip = true
+			}
+		}
+	}
+
+	if container {
+		if max := v.limits.MaxDepth; max > 0 && v.base >= 0 {
+			switch depth := len(v.stack) - v.base; {
+			case depth >= 2*max:
+				return false, errMaxDepth
+			case depth >= max && !skip:
+				v.truncated = true
+				if err := v.out.OnString(truncatedMarker); err != nil {
+					return false, err
+				}
+				skip = true
+			}
+		}
+		v.stack = append(v.stack, limitFrame{array: array, skip: skip})
+	}
+	return !skip, nil
+}
+
+func (v *limitVisitor) OnObjectStart(len int, baseType structform.BaseType) error {
+	if ok, err := v.begin(true, false); !ok {
+		return err
+	}
+	if v.limits.MaxDepth > 0 {
+		len = -1
+	}
+	return v.out.OnObjectStart(len, baseType)
+}
+
+func (v *limitVisitor) OnObjectFinished() error {
+	frame := v.stack[len(v.stack)-1]
+	v.stack = v.stack[:len(v.stack)-1]
+	if frame.skip {
+		return nil
+	}
+	return v.out.OnObjectFinished()
+}
+
+func (v *limitVisitor) OnKey(key string) error {
+	if v.skipping() {
+		return nil
+	}
+	return v.out.OnKey(key)
+}
+
+func (v *limitVisitor) OnArrayStart(len int, baseType structform.BaseType) error {
+	if ok, err := v.begin(true, true); !ok {
+		return err
+	}
+	if v.limits.MaxDepth > 0 {
+		len = -1
+	} else if v.limits.MaxArrayLen > 0 && len > v.limits.MaxArrayLen {
+		len = v.limits.MaxArrayLen
+	}
+	return v.out.OnArrayStart(len, baseType)
+}
+
+func (v *limitVisitor) OnArrayFinished() error {
+	frame := v.stack[len(v.stack)-1]
+	v.stack = v.stack[:len(v.stack)-1]
+	if frame.skip {
+		return nil
+	}
+	return v.out.OnArrayFinished()
+}
+
+func (v *limitVisitor) OnNil() error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnNil()
+}
+
+func (v *limitVisitor) OnBool(b bool) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnBool(b)
+}
+
+func (v *limitVisitor) OnString(s string) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	s, truncated := truncateString(s, v.maxString)
+	v.truncated = v.truncated || truncated
+	return v.out.OnString(s)
+}
+
+func (v *limitVisitor) OnInt8(i int8) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnInt8(i)
+}
+
+func (v *limitVisitor) OnInt16(i int16) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnInt16(i)
+}
+
+func (v *limitVisitor) OnInt32(i int32) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnInt32(i)
+}
+
+func (v *limitVisitor) OnInt64(i int64) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnInt64(i)
+}
+
+func (v *limitVisitor) OnInt(i int) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnInt(i)
+}
+
+func (v *limitVisitor) OnByte(b byte) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnByte(b)
+}
+
+func (v *limitVisitor) OnUint8(u uint8) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnUint8(u)
+}
+
+func (v *limitVisitor) OnUint16(u uint16) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnUint16(u)
+}
+
+func (v *limitVisitor) OnUint32(u uint32) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnUint32(u)
+}
+
+func (v *limitVisitor) OnUint64(u uint64) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnUint64(u)
+}
+
+func (v *limitVisitor) OnUint(u uint) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnUint(u)
+}
+
+func (v *limitVisitor) OnFloat32(f float32) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnFloat32(f)
+}
+
+func (v *limitVisitor) OnFloat64(f float64) error {
+	if ok, err := v.begin(false, false); !ok {
+		return err
+	}
+	return v.out.OnFloat64(f)
+}
@@ -0,0 +1,221 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/elastic/go-structform/cborl"
+	"github.com/elastic/go-structform/gotype"
+)
+
+type limitNode map[string]interface{}
+
+type limitTree struct {
+	Sub  *limitTree `struct:"sub,omitempty"`
+	Name string     `struct:"name"`
+}
+
+func TestLimitsSelfReference(t *testing.T) {
+	self := map[string]interface{}{"a": 1}
+	self["self"] = self
+
+	layouts := map[string]Factory{
+		"json":    JSON(nil, Limit(Limits{MaxDepth: 4})),
+		"text":    Text(true, Limit(Limits{MaxDepth: 4})),
+		"logfmt":  Logfmt(nil, Limit(Limits{MaxDepth: 4})),
+		"gelf":    GELF("host", nil, Limit(Limits{MaxDepth: 4})),
+		"pretty":  Pretty(Limit(Limits{MaxDepth: 4})),
+		"default": Text(true),
+	}
+
+	for name, factory := range layouts {
+		factory := factory
+		t.Run(name, func(t *testing.T) {
+			out := logString(t, factory, testMessage("hello", "v", self))
+			if !strings.Contains(out, truncatedMarker) {
+				t.Errorf("missing truncation marker in %q", out)
+			}
+		})
+	}
+}
+
+func TestLimitsText(t *testing.T) {
+	cases := map[string]struct {
+		limits Limits
+		value  interface{}
+		want   string
+	}{
+		"depth": {
+			limits: Limits{MaxDepth: 2},
+			value:  map[string]interface{}{"a": map[string]interface{}{"b": map[string]int{"c": 1}}},
+			want:   `v={a={b=…(truncated)}}`,
+		},
+		"string": {
+			limits: Limits{MaxStringLen: 5},
+			value:  "hello world",
+			want:   `v="hello…(truncated)"`,
+		},
+		"array": {
+			limits: Limits{MaxArrayLen: 2},
+			value:  []int{1, 2, 3},
+			want:   `v=[1 2 …(truncated)]`,
+		},
+		"nested array": {
+			limits: Limits{MaxArrayLen: 1},
+			value:  map[string][]string{"a": {"x", "y"}},
+			want:   `v={a=["x" …(truncated)]}`,
+		},
+		"struct": {
+			value: struct {
+				A int
+				B []string
+				c int
+			}{1, []string{"x"}, 2},
+			want: `v={A=1 B=["x"]}`,
+		},
+		"unlimited": {
+			limits: Limits{MaxDepth: -1},
+			value:  []interface{}{[]int{1}, map[string]int{"b": 2, "a": 1}},
+			want:   `v=[[1] {a=1 b=2}]`,
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			out := logString(t, Text(true, Limit(test.limits)), testMessage("hello", "v", test.value))
+			if !strings.Contains(out, test.want) {
+				t.Errorf("missing %v in %q", test.want, out)
+			}
+		})
+	}
+}
+
+func TestLimitsStructured(t *testing.T) {
+	cases := map[string]struct {
+		limits    Limits
+		value     interface{}
+		want      interface{}
+		truncated bool
+	}{
+		"none": {
+			value: []int{1, 2, 3},
+			want:  []interface{}{float64(1), float64(2), float64(3)},
+		},
+		"depth": {
+			limits:    Limits{MaxDepth: 1},
+			value:     map[string]interface{}{"a": map[string]int{"b": 1}},
+			want:      map[string]interface{}{"a": truncatedMarker},
+			truncated: true,
+		},
+		"string": {
+			limits:    Limits{MaxStringLen: 5},
+			value:     "hello world",
+			want:      "hello" + truncatedMarker,
+			truncated: true,
+		},
+		"array": {
+			limits:    Limits{MaxArrayLen: 2},
+			value:     []int{1, 2, 3},
+			want:      []interface{}{float64(1), float64(2)},
+			truncated: true,
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			event := logJSON(t, JSON(nil, Limit(test.limits)), testMessage("hello", "v", test.value))
+			if got, _ := lookup(event, "fields.v"); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %#v, want %#v", got, test.want)
+			}
+			if got, _ := lookup(event, "log.truncated"); (got == true) != test.truncated {
+				t.Errorf("log.truncated: got %v, want %v", got, test.truncated)
+			}
+		})
+	}
+}
+
+func TestLimitsSiblings(t *testing.T) {
+	deep := limitNode{"c": limitNode{"d": 1}}
+
+	cases := map[string]struct {
+		value interface{}
+		want  interface{}
+	}{
+		"map": {
+			value: limitNode{"a": limitNode{"b": deep, "y": nil, "x": nil, "w": nil}},
+			want: map[string]interface{}{
+				"a": map[string]interface{}{"b": truncatedMarker, "y": nil, "x": nil, "w": nil},
+			},
+		},
+		"struct": {
+			value: limitTree{Name: "a", Sub: &limitTree{Name: "b", Sub: &limitTree{Name: "c"}}},
+			want: map[string]interface{}{
+				"name": "a",
+				"sub":  map[string]interface{}{"name": "b", "sub": truncatedMarker},
+			},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		opt := Limit(Limits{MaxDepth: 2})
+		msg := testMessage("hello", "v", test.value)
+
+		t.Run(name+"/json", func(t *testing.T) {
+			event := logJSON(t, JSON(nil, opt), msg)
+			if got, _ := lookup(event, "fields.v"); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %#v, want %#v", got, test.want)
+			}
+		})
+
+		t.Run(name+"/cbor", func(t *testing.T) {
+			out := logString(t, CBOR(nil, opt), msg)
+
+			var event map[string]interface{}
+			u, err := gotype.NewUnfolder(&event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := cborl.ParseString(out, u); err != nil {
+				t.Fatalf("invalid CBOR: %v", err)
+			}
+			if got, _ := lookup(event, "fields.v"); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLimitsEventSize(t *testing.T) {
+	msg := testMessage("hello", "v", strings.Repeat("x", 1000))
+
+	t.Run("json", func(t *testing.T) {
+		event := logJSON(t, JSON(nil, Limit(Limits{MaxEventSize: 500})), msg)
+		if got, _ := lookup(event, "message"); got != "hello" {
+			t.Errorf("message: got %v", got)
+		}
+		if _, ok := lookup(event, "fields.v"); ok {
+			t.Error("context not dropped")
+		}
+		if got, _ := lookup(event, "log.truncated"); got != true {
+			t.Errorf("log.truncated: got %v", got)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		out := logString(t, Text(true, Limit(Limits{MaxEventSize: 100})), msg)
+		line := strings.TrimSuffix(out, "\n")
+		if !strings.HasSuffix(line, truncatedMarker) || len(line) != 100+len(truncatedMarker) {
+			t.Errorf("line not truncated: %q", out)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"io"
+	"strconv"
+	"unicode/utf8"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/urso/diag"
+)
+
+// logfmtVisitor serializes a structured event as a single logfmt line.
+// Nested objects and arrays are flattened into dotted keys. Array elements
+// use their index as key.
+type logfmtVisitor struct {
+	out     io.Writer
+	line    []byte
+	prefix  []byte
+	stack   []logfmtFrame
+	written int
+}
+
+// logfmtFrame stores the state of an object or array while serializing
+// nested values.
+type logfmtFrame struct {
+	base  int // length of the key prefix of the object or array
+	array bool
+	idx   int
+}
+
+// Logfmt creates a layout writing one logfmt formatted line per event. The
+// keys match the fields produced by the JSON layout, with nested objects being
+// flattened into dotted keys. Lines are terminated via FrameNewline, unless
+// configured otherwise via Framed.
+func Logfmt(fields []diag.Field, opts ...Option) Factory {
+	return Structured(func(w io.Writer) structform.Visitor {
+		return newLogfmtVisitor(w)
+	}, fields, opts...)
+}
+
+func newLogfmtVisitor(out io.Writer) *logfmtVisitor {
+	return &logfmtVisitor{out: out}
+}
+
+func (v *logfmtVisitor) OnObjectStart(_ int, _ structform.BaseType) error {
+	if len(v.stack) == 0 {
+		v.line = v.line[:0]
+		v.prefix = v.prefix[:0]
+		v.written = 0
+	} else {
+		v.beginValue()
+	}
+	v.stack = append(v.stack, logfmtFrame{base: len(v.prefix)})
+	return nil
+}
+
+func (v *logfmtVisitor) OnObjectFinished() error {
+	v.stack = v.stack[:len(v.stack)-1]
+	if len(v.stack) > 0 {
+		return nil
+	}
+
+	_, err := v.out.Write(v.line)
+	return err
+}
+
+func (v *logfmtVisitor) OnKey(key string) error {
+	frame := &v.stack[len(v.stack)-1]
+	v.prefix = v.prefix[:frame.base]
+	if frame.base > 0 {
+		v.prefix = append(v.prefix, '.')
+	}
+	v.prefix = append(v.prefix, key...)
+	return nil
+}
+
+func (v *logfmtVisitor) OnArrayStart(_ int, _ structform.BaseType) error {
+	v.beginValue()
+	v.stack = append(v.stack, logfmtFrame{base: len(v.prefix), array: true})
+	return nil
+}
+
+func (v *logfmtVisitor) OnArrayFinished() error {
+	v.stack = v.stack[:len(v.stack)-1]
+	return nil
+}
+
+// beginValue updates the key prefix for the next array element.
+func (v *logfmtVisitor) beginValue() {
+	if len(v.stack) == 0 {
+		return
+	}
+
+	frame := &v.stack[len(v.stack)-1]
+	if !frame.array {
+		return
+	}
+
+	v.prefix = v.prefix[:frame.base]
+	if frame.base > 0 {
+		v.prefix = append(v.prefix, '.')
+	}
+	v.prefix = strconv.AppendInt(v.prefix, int64(frame.idx), 10)
+	frame.idx++
+}
+
+func (v *logfmtVisitor) beginPair() {
+	v.beginValue()
+	if v.written > 0 {
+		v.line = append(v.line, ' ')
+	}
+	v.written++
+
+	for _, c := range v.prefix {
+		if c <= ' ' || c == '=' || c == '"' || c == 0x7f {
+			c = '_'
+		}
+		v.line = append(v.line, c)
+	}
+	v.line = append(v.line, '=')
+}
+
+func (v *logfmtVisitor) OnNil() error {
+	v.beginPair()
+	return nil
+}
+
+func (v *logfmtVisitor) OnBool(b bool) error {
+	v.beginPair()
+	v.line = strconv.AppendBool(v.line, b)
+	return nil
+}
+
+func (v *logfmtVisitor) OnString(s string) error {
+	v.beginPair()
+	if logfmtNeedsQuote(s) {
+		v.line = strconv.AppendQuote(v.line, s)
+	} else {
+		v.line = append(v.line, s...)
+	}
+	return nil
+}
+
+func (v *logfmtVisitor) OnInt8(i int8) error   { return v.onInt(int64(i)) }
+func (v *logfmtVisitor) OnInt16(i int16) error { return v.onInt(int64(i)) }
+func (v *logfmtVisitor) OnInt32(i int32) error { return v.onInt(int64(i)) }
+func (v *logfmtVisitor) OnInt64(i int64) error { return v.onInt(i) }
+func (v *logfmtVisitor) OnInt(i int) error     { return v.onInt(int64(i)) }
+
+func (v *logfmtVisitor) onInt(i int64) error {
+	v.beginPair()
+	v.line = strconv.AppendInt(v.line, i, 10)
+	return nil
+}
+
+func (v *logfmtVisitor) OnByte(b byte) error     { return v.onUint(uint64(b)) }
+func (v *logfmtVisitor) OnUint8(u uint8) error   { return v.onUint(uint64(u)) }
+func (v *logfmtVisitor) OnUint16(u uint16) error { return v.onUint(uint64(u)) }
+func (v *logfmtVisitor) OnUint32(u uint32) error { return v.onUint(uint64(u)) }
+func (v *logfmtVisitor) OnUint64(u uint64) error { return v.onUint(u) }
+func (v *logfmtVisitor) OnUint(u uint) error     { return v.onUint(uint64(u)) }
+
+func (v *logfmtVisitor) onUint(u uint64) error {
+	v.beginPair()
+	v.line = strconv.AppendUint(v.line, u, 10)
+	return nil
+}
+
+func (v *logfmtVisitor) OnFloat32(f float32) error { return v.onFloat(float64(f), 32) }
+func (v *logfmtVisitor) OnFloat64(f float64) error { return v.onFloat(f, 64) }
+
+func (v *logfmtVisitor) onFloat(f float64, bits int) error {
+	v.beginPair()
+	v.line = strconv.AppendFloat(v.line, f, 'g', -1, bits)
+	return nil
+}
+
+// logfmtNeedsQuote checks if a value must be quoted. Empty strings, strings
+// with spaces, '=', '"', control characters, or invalid UTF-8 are quoted.
+func logfmtNeedsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c <= ' ' || c == '=' || c == '"' || c == '\\' || c == 0x7f {
+				return true
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || isControl(r) {
+			return true
+		}
+		i += size
+	}
+	return false
+}
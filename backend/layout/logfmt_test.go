@@ -0,0 +1,75 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/urso/diag-ecs/ecs"
+	"github.com/urso/sderr"
+)
+
+func TestLogfmt(t *testing.T) {
+	msg := testMessage(`hello "world"`,
+		"empty", "",
+		"eq", "a=b",
+		"space", "a b",
+		"tags", []string{"a", "b"},
+		"ratio", 1.5,
+		"ok", true,
+		ecs.Host.Hostname("h1"),
+	)
+	msg.Causes = []error{sderr.Wrap(errors.New("inner"), "outer")}
+
+	line := logString(t, Logfmt(nil), msg)
+	if strings.Count(line, "\n") != 1 || !strings.HasSuffix(line, "\n") {
+		t.Fatalf("expected a single line, got %q", line)
+	}
+
+	pairs := []string{
+		`message="hello \"world\""`,
+		`log.level=info`,
+		`log.logger=test`,
+		`host.hostname=h1`,
+		`fields.empty=""`,
+		`fields.eq="a=b"`,
+		`fields.space="a b"`,
+		`fields.tags.0=a`,
+		`fields.tags.1=b`,
+		`fields.ratio=1.5`,
+		`fields.ok=true`,
+		`error.message=outer`,
+		`error.cause.message=inner`,
+	}
+	for _, pair := range pairs {
+		if !strings.Contains(" "+line, " "+pair+" ") && !strings.HasSuffix(line, " "+pair+"\n") {
+			t.Errorf("missing %v in %q", pair, line)
+		}
+	}
+}
+
+func TestLogfmtNeedsQuote(t *testing.T) {
+	cases := map[string]bool{
+		"plain":      false,
+		"ünïcode":    false,
+		"":           true,
+		"a b":        true,
+		"a=b":        true,
+		`a"b`:        true,
+		`a\b`:        true,
+		"tab\there":  true,
+		"del\x7f":    true,
+		"bad\xffutf": true,
+	}
+	for s, want := range cases {
+		if got := logfmtNeedsQuote(s); got != want {
+			t.Errorf("%q: got %v, want %v", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,524 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"encoding"
+	stdjson "encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/elastic/go-structform/json"
+	"github.com/urso/diag"
+)
+
+// LogMarshaler is implemented by types that report their own fields. Layouts
+// call MarshalLog instead of serializing the value via reflection, such that
+// types can choose which fields are logged.
+type LogMarshaler interface {
+	MarshalLog(enc ObjectEncoder) error
+}
+
+// ObjectEncoder writes the fields of an object. Errors are reported by
+// MarshalLog, or when the object is finished.
+type ObjectEncoder interface {
+	AddString(key, value string)
+	AddBool(key string, value bool)
+	AddInt64(key string, value int64)
+	AddUint64(key string, value uint64)
+	AddFloat64(key string, value float64)
+
+	// AddObject adds a nested object.
+	AddObject(key string, obj LogMarshaler)
+
+	// AddAny adds a value of any type. The value is reported like a field
+	// value, using registered value encoders and marshalers.
+	AddAny(key string, value interface{})
+}
+
+// Marshaler selects an interface types can implement to control how values
+// are reported.
+type Marshaler uint8
+
+const (
+	// MarshalLog uses the LogMarshaler interface.
+	MarshalLog Marshaler = iota
+
+	// MarshalJSON uses the json.Marshaler interface. The JSON document is
+	// reported as is by text layouts.
+	MarshalJSON
+
+	// MarshalText uses the encoding.TextMarshaler interface. The value is
+	// reported as string.
+	MarshalText
+
+	// MarshalString uses the fmt.Stringer interface. The value is reported as
+	// string.
+	MarshalString
+)
+
+var defaultMarshalers = []Marshaler{MarshalLog, MarshalJSON, MarshalText, MarshalString}
+
+// MarshalerPriority configures the marshaler interfaces checked, in order of
+// priority. Interfaces not listed are ignored. Without arguments, all values
+// are serialized via reflection. The default order is MarshalLog,
+// MarshalJSON, MarshalText, MarshalString.
+//
+// Marshalers are only used for values of kind struct, pointer, map, slice,
+// or array. Numbers, strings, and booleans are always reported as is.
+// Registered value encoders take precedence over marshalers.
+func MarshalerPriority(order ...Marshaler) Option {
+	return func(o *options) error {
+		for _, m := range order {
+			if m > MarshalString {
+				return fmt.Errorf("unknown marshaler %v", m)
+			}
+		}
+		o.encoders.marshalers = append([]Marshaler(nil), order...)
+		return nil
+	}
+}
+
+// marshaler finds the first marshaler interface implemented by v.
+func (e valueEncoders) marshaler(v interface{}) (Marshaler, bool) {
+	if len(e.marshalers) == 0 || v == nil {
+		return 0, false
+	}
+
+	// Nil values are reported as is, as marshalers with value receivers
+	// would panic when called via a nil pointer.
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if isNil(v) {
+			return 0, false
+		}
+	case reflect.Struct, reflect.Array:
+	default:
+		return 0, false
+	}
+
+	for _, m := range e.marshalers {
+		var ok bool
+		switch m {
+		case MarshalLog:
+			_, ok = v.(LogMarshaler)
+		case MarshalJSON:
+			_, ok = v.(stdjson.Marshaler)
+		case MarshalText:
+			_, ok = v.(encoding.TextMarshaler)
+		case MarshalString:
+			_, ok = v.(fmt.Stringer)
+		}
+		if ok {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// isNil reports whether v is nil or a nil pointer, map, or slice.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// encodeString converts a field value into a string, for layouts reporting
+// all values as strings. Value encoders and marshalers are applied like in
+// the text layouts, but strings are not quoted.
+func (e valueEncoders) encodeString(v interface{}, limits Limits) (string, error) {
+	v = e.encode(v)
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case []byte:
+		return string(val), nil
+	}
+
+	if m, ok := e.marshaler(v); ok {
+		switch m {
+		case MarshalText:
+			b, err := v.(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return "", err
+			}
+			s, _ := truncateString(string(b), limits.MaxStringLen)
+			return s, nil
+		case MarshalString:
+			s, _ := truncateString(v.(fmt.Stringer).String(), limits.MaxStringLen)
+			return s, nil
+		}
+	}
+
+	var buf bytes.Buffer
+	p := &textCtxPrinter{buf: &buf, encoders: e, limits: limits}
+	if err := p.writeMarshaled(v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// foldValue serializes a value using the first applicable marshaler. Values
+// not implementing any marshaler are serialized via reflection.
+func (v structVisitor) foldValue(ifc interface{}) error {
+	if isNil(ifc) {
+		return v.visitor.OnNil()
+	}
+
+	m, ok := v.encoders.marshaler(ifc)
+	if !ok {
+		if isRecursiveType(reflect.TypeOf(ifc)) {
+			return foldRecursive(v.visitor, reflect.ValueOf(ifc))
+		}
+		if v.order != OrderDefault {
+			return foldSorted(v.visitor, ifc, v.typeOpts, v.limits.MaxDepth)
+		}
+		return v.types.Fold(ifc)
+	}
+
+	switch m {
+	case MarshalLog:
+		enc := &structObjEncoder{v: v}
+		return enc.object(ifc.(LogMarshaler))
+	case MarshalJSON:
+		b, err := ifc.(stdjson.Marshaler).MarshalJSON()
+		if err != nil {
+			return err
+		}
+		return json.Parse(b, v.visitor)
+	case MarshalText:
+		b, err := ifc.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		return v.visitor.OnString(string(b))
+	default:
+		return v.visitor.OnString(ifc.(fmt.Stringer).String())
+	}
+}
+
+// structObjEncoder writes the fields reported by a LogMarshaler to the
+// structured layout. The first error is recorded, all later calls are
+// ignored.
+type structObjEncoder struct {
+	v   structVisitor
+	err error
+}
+
+func (e *structObjEncoder) object(obj LogMarshaler) error {
+	if err := e.v.visitor.OnObjectStart(-1, 0); err != nil {
+		return err
+	}
+	if err := obj.MarshalLog(e); err != nil {
+		return err
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return e.v.visitor.OnObjectFinished()
+}
+
+func (e *structObjEncoder) key(key string) bool {
+	if e.err == nil {
+		e.err = e.v.visitor.OnKey(key)
+	}
+	return e.err == nil
+}
+
+func (e *structObjEncoder) AddString(key, value string) {
+	if e.key(key) {
+		e.err = e.v.visitor.OnString(value)
+	}
+}
+
+func (e *structObjEncoder) AddBool(key string, value bool) {
+	if e.key(key) {
+		e.err = e.v.visitor.OnBool(value)
+	}
+}
+
+func (e *structObjEncoder) AddInt64(key string, value int64) {
+	if e.key(key) {
+		e.err = e.v.visitor.OnInt64(value)
+	}
+}
+
+func (e *structObjEncoder) AddUint64(key string, value uint64) {
+	if e.key(key) {
+		e.err = e.v.visitor.OnUint64(value)
+	}
+}
+
+func (e *structObjEncoder) AddFloat64(key string, value float64) {
+	if e.key(key) {
+		e.err = e.v.visitor.OnFloat64(value)
+	}
+}
+
+func (e *structObjEncoder) AddObject(key string, obj LogMarshaler) {
+	if e.key(key) {
+		nested := &structObjEncoder{v: e.v}
+		e.err = nested.object(obj)
+	}
+}
+
+func (e *structObjEncoder) AddAny(key string, value interface{}) {
+	if e.key(key) {
+		e.err = e.v.foldValue(e.v.encoders.encode(value))
+	}
+}
+
+// writeValue prints a value in the text layouts. Strings are quoted, nested
+// contexts and objects are enclosed in braces.
+func (p *textCtxPrinter) writeValue(value interface{}) (err error) {
+	switch v := p.encoders.encode(value).(type) {
+	case *diag.Context:
+		return p.writeNested(func() error {
+			return v.VisitKeyValues(p)
+		})
+	case string:
+		p.writeQuoted(v)
+		return nil
+	case []byte:
+		p.writeQuoted(string(v))
+		return nil
+	default:
+		return p.writeMarshaled(v)
+	}
+}
+
+func (p *textCtxPrinter) writeMarshaled(value interface{}) error {
+	if isNil(value) {
+		p.buf.WriteString("<nil>")
+		return nil
+	}
+
+	m, ok := p.encoders.marshaler(value)
+	if !ok {
+		return p.writeReflect(reflect.ValueOf(value))
+	}
+
+	switch m {
+	case MarshalLog:
+		return p.writeNested(func() error {
+			enc := &textObjEncoder{p: p}
+			if err := value.(LogMarshaler).MarshalLog(enc); err != nil {
+				return err
+			}
+			return enc.err
+		})
+	case MarshalJSON:
+		b, err := value.(stdjson.Marshaler).MarshalJSON()
+		if err != nil {
+			return err
+		}
+		p.writeLimited(string(b))
+	case MarshalText:
+		b, err := value.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		p.writeQuoted(string(b))
+	default:
+		p.writeQuoted(value.(fmt.Stringer).String())
+	}
+	return nil
+}
+
+// writeNested prints an object enclosed in braces. Objects nested deeper than
+// the configured limit are replaced by the truncation marker.
+func (p *textCtxPrinter) writeNested(fn func() error) error {
+	return p.writeEnclosed('{', '}', fn)
+}
+
+// writeEnclosed prints an object or array enclosed in open and close.
+func (p *textCtxPrinter) writeEnclosed(open, close byte, fn func() error) error {
+	if p.atMaxDepth() {
+		p.buf.WriteString(truncatedMarker)
+		return nil
+	}
+
+	p.depth++
+	p.buf.WriteByte(open)
+	err := fn()
+	p.buf.WriteByte(close)
+	p.depth--
+	return err
+}
+
+func (p *textCtxPrinter) atMaxDepth() bool {
+	return p.limits.MaxDepth > 0 && p.depth >= p.limits.MaxDepth
+}
+
+func (p *textCtxPrinter) writeQuoted(s string) {
+	s, _ = truncateString(s, p.limits.MaxStringLen)
+	p.buf.WriteString(strconv.Quote(s))
+}
+
+// writeLimited prints an unquoted value. Control characters are escaped if
+// configured.
+func (p *textCtxPrinter) writeLimited(s string) {
+	s, _ = truncateString(s, p.limits.MaxStringLen)
+	p.buf.WriteString(escapeText(s, p.escape))
+}
+
+// writeReflect prints values not implementing any marshaler. Maps and
+// structs are printed like nested objects, and arrays as space-separated
+// list in brackets. Elements are printed via writeValue, such that encoders
+// and marshalers are applied to nested values too. Nesting is bounded by the
+// depth limit, such that self-referencing values can be printed.
+func (p *textCtxPrinter) writeReflect(rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			p.buf.WriteString("<nil>")
+			return nil
+		}
+		if p.atMaxDepth() {
+			p.buf.WriteString(truncatedMarker)
+			return nil
+		}
+		p.depth++
+		err := p.writeValue(rv.Elem().Interface())
+		p.depth--
+		return err
+
+	case reflect.Slice, reflect.Array:
+		return p.writeEnclosed('[', ']', func() error {
+			n := rv.Len()
+			if max := p.limits.MaxArrayLen; max > 0 && n > max {
+				n = max
+			}
+			for i := 0; i < n; i++ {
+				if i > 0 {
+					p.buf.WriteByte(' ')
+				}
+				if err := p.writeValue(rv.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+			if n < rv.Len() {
+				p.buf.WriteByte(' ')
+				p.buf.WriteString(truncatedMarker)
+			}
+			return nil
+		})
+
+	case reflect.Map:
+		return p.writeNested(func() error {
+			keys := make([]string, rv.Len())
+			values := make(map[string]reflect.Value, rv.Len())
+			iter := rv.MapRange()
+			for i := 0; iter.Next(); i++ {
+				key := fmt.Sprint(iter.Key().Interface())
+				keys[i], values[key] = key, iter.Value()
+			}
+			sort.Strings(keys)
+
+			enc := &textObjEncoder{p: p}
+			for _, key := range keys {
+				enc.AddAny(key, values[key].Interface())
+			}
+			return enc.err
+		})
+
+	case reflect.Struct:
+		return p.writeNested(func() error {
+			enc := &textObjEncoder{p: p}
+			t := rv.Type()
+			for i := 0; i < t.NumField(); i++ {
+				if field := t.Field(i); field.PkgPath == "" {
+					enc.AddAny(field.Name, rv.Field(i).Interface())
+				}
+			}
+			return enc.err
+		})
+
+	default:
+		p.writeLimited(fmt.Sprint(rv.Interface()))
+		return nil
+	}
+}
+
+// textObjEncoder prints the fields reported by a LogMarshaler as
+// space-separated key=value pairs.
+type textObjEncoder struct {
+	p   *textCtxPrinter
+	n   int
+	err error
+}
+
+func (e *textObjEncoder) key(key string) bool {
+	if e.err != nil {
+		return false
+	}
+	if e.n > 0 {
+		e.p.buf.WriteByte(' ')
+	}
+	e.p.buf.WriteString(escapeText(key, e.p.escape))
+	e.p.buf.WriteByte('=')
+	e.n++
+	return true
+}
+
+func (e *textObjEncoder) AddString(key, value string) {
+	if e.key(key) {
+		e.p.writeQuoted(value)
+	}
+}
+
+func (e *textObjEncoder) AddBool(key string, value bool) {
+	if e.key(key) {
+		e.p.buf.WriteString(strconv.FormatBool(value))
+	}
+}
+
+func (e *textObjEncoder) AddInt64(key string, value int64) {
+	if e.key(key) {
+		e.p.buf.WriteString(strconv.FormatInt(value, 10))
+	}
+}
+
+func (e *textObjEncoder) AddUint64(key string, value uint64) {
+	if e.key(key) {
+		e.p.buf.WriteString(strconv.FormatUint(value, 10))
+	}
+}
+
+func (e *textObjEncoder) AddFloat64(key string, value float64) {
+	if e.key(key) {
+		e.p.buf.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	}
+}
+
+func (e *textObjEncoder) AddObject(key string, obj LogMarshaler) {
+	if !e.key(key) {
+		return
+	}
+
+	e.err = e.p.writeNested(func() error {
+		nested := &textObjEncoder{p: e.p}
+		if err := obj.MarshalLog(nested); err != nil {
+			return err
+		}
+		return nested.err
+	})
+}
+
+func (e *textObjEncoder) AddAny(key string, value interface{}) {
+	if e.key(key) {
+		e.err = e.p.writeValue(value)
+	}
+}
@@ -0,0 +1,121 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// allMarshalers implements all marshaler interfaces with value receivers.
+type allMarshalers struct{ A int }
+
+func (allMarshalers) MarshalLog(enc ObjectEncoder) error {
+	enc.AddString("via", "log")
+	return nil
+}
+func (allMarshalers) MarshalJSON() ([]byte, error) { return []byte(`{"via":"json"}`), nil }
+func (allMarshalers) MarshalText() ([]byte, error) { return []byte("via text"), nil }
+func (allMarshalers) String() string               { return "via string" }
+
+type textMap map[string]int
+
+func (m textMap) MarshalText() ([]byte, error) { return []byte("len=" + string(rune('0'+len(m)))), nil }
+
+type stringSlice []int
+
+func (s stringSlice) String() string { return "slice" }
+
+func TestMarshalerPriority(t *testing.T) {
+	cases := map[string]struct {
+		opts []Option
+		json interface{}
+		text string
+	}{
+		"default": {
+			json: map[string]interface{}{"via": "log"},
+			text: `v={via="log"}`,
+		},
+		"json": {
+			opts: []Option{MarshalerPriority(MarshalJSON, MarshalLog)},
+			json: map[string]interface{}{"via": "json"},
+			text: `v={"via":"json"}`,
+		},
+		"text": {
+			opts: []Option{MarshalerPriority(MarshalText, MarshalString)},
+			json: "via text",
+			text: `v="via text"`,
+		},
+		"stringer": {
+			opts: []Option{MarshalerPriority(MarshalString)},
+			json: "via string",
+			text: `v="via string"`,
+		},
+		"reflection": {
+			opts: []Option{MarshalerPriority()},
+			json: map[string]interface{}{"a": float64(1)},
+			text: `v={A=1}`,
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("hello", "v", allMarshalers{A: 1})
+
+			event := logJSON(t, JSON(nil, test.opts...), msg)
+			if got, _ := lookup(event, "fields.v"); !reflect.DeepEqual(got, test.json) {
+				t.Errorf("json: got %#v, want %#v", got, test.json)
+			}
+
+			if out := logString(t, Text(true, test.opts...), msg); !strings.Contains(out, test.text) {
+				t.Errorf("text: missing %v in %q", test.text, out)
+			}
+		})
+	}
+}
+
+func TestMarshalerFallback(t *testing.T) {
+	cases := map[string]struct {
+		value interface{}
+		json  interface{}
+		text  string
+	}{
+		"text map":         {textMap{"a": 1}, "len=1", `v="len=1"`},
+		"stringer slice":   {stringSlice{1}, "slice", `v="slice"`},
+		"time":             {time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), "2020-01-02T03:04:05Z", `v="2020-01-02T03:04:05Z"`},
+		"pointer":          {&allMarshalers{}, map[string]interface{}{"via": "log"}, `v={via="log"}`},
+		"nil time pointer": {(*time.Time)(nil), nil, `v=<nil>`},
+		"nil pointer":      {(*allMarshalers)(nil), nil, `v=<nil>`},
+		"nil map":          {textMap(nil), nil, `v=<nil>`},
+		"nil slice":        {stringSlice(nil), nil, `v=<nil>`},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("hello", "v", test.value)
+
+			event := logJSON(t, JSON(nil), msg)
+			if got, _ := lookup(event, "fields.v"); !reflect.DeepEqual(got, test.json) {
+				t.Errorf("json: got %#v, want %#v", got, test.json)
+			}
+
+			if out := logString(t, Text(true), msg); !strings.Contains(out, test.text) {
+				t.Errorf("text: missing %v in %q", test.text, out)
+			}
+		})
+	}
+}
+
+func TestMarshalerPriorityInvalid(t *testing.T) {
+	if _, err := JSON(nil, MarshalerPriority(MarshalString+1))(nil); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,241 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	structform "github.com/elastic/go-structform"
+	"github.com/elastic/go-structform/gotype"
+	"github.com/urso/diag"
+)
+
+// FieldOrder selects the order fields are reported in.
+type FieldOrder uint8
+
+const (
+	// OrderDefault reports context fields sorted by key, as provided by
+	// diag.Context. Keys of Go maps passed as field values are reported in
+	// random order.
+	OrderDefault FieldOrder = iota
+
+	// OrderLexicographic reports all fields sorted by key, including the keys
+	// of Go maps passed as field values.
+	OrderLexicographic
+
+	// OrderECS reports the ECS base fields `@timestamp`, `log.level`, and
+	// `message` first. All other fields are reported in lexicographic order.
+	OrderECS
+)
+
+// ecsFieldPriority lists the fields reported first if OrderECS is used.
+var ecsFieldPriority = []string{"@timestamp", "log.level", "message"}
+
+type keyValue struct {
+	key   string
+	value diag.Value
+}
+
+// SortFields configures the structured and text layouts to report fields in
+// a stable order. Only the order of fields changes. If a context contains
+// duplicate keys, the most recent field is reported, as before.
+func SortFields(order FieldOrder) Option {
+	return func(o *options) error {
+		o.order = order
+		return nil
+	}
+}
+
+// visitOrdered reports the fields in ctx to v. The order of fields is only
+// changed for OrderECS, as diag.Context already reports fields sorted by
+// key.
+func visitOrdered(ctx *diag.Context, order FieldOrder, structured bool, v diag.Visitor) error {
+	if order != OrderECS {
+		if structured {
+			return ctx.VisitStructured(v)
+		}
+		return ctx.VisitKeyValues(v)
+	}
+
+	var fields []keyValue
+	ctx.VisitKeyValues(visitValues(func(key string, v diag.Value) error {
+		fields = append(fields, keyValue{key, v})
+		return nil
+	}))
+	sort.SliceStable(fields, func(i, j int) bool {
+		return ecsKeyLess(fields[i].key, fields[j].key)
+	})
+
+	if !structured {
+		for _, fld := range fields {
+			if err := v.OnValue(fld.key, fld.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visitStructuredFields(fields, v)
+}
+
+// ecsKeyLess compares keys segment by segment, such that fields sharing a
+// common object stay adjacent. Segments are ranked by ecsFieldPriority first.
+func ecsKeyLess(a, b string) bool {
+	prefixA, prefixB := "", ""
+	for {
+		segA, restA, moreA := cutSegment(a)
+		segB, restB, moreB := cutSegment(b)
+		prefixA += segA
+		prefixB += segB
+
+		if segA != segB {
+			rankA, rankB := ecsRank(prefixA), ecsRank(prefixB)
+			if rankA != rankB {
+				return rankA < rankB
+			}
+			return segA < segB
+		}
+		if !moreA || !moreB {
+			return !moreA && moreB
+		}
+
+		a, b = restA, restB
+		prefixA += "."
+		prefixB += "."
+	}
+}
+
+func cutSegment(key string) (seg, rest string, more bool) {
+	if idx := strings.IndexByte(key, '.'); idx >= 0 {
+		return key[:idx], key[idx+1:], true
+	}
+	return key, "", false
+}
+
+// ecsRank returns the position of the first prioritized field that is equal
+// to, or nested in prefix.
+func ecsRank(prefix string) int {
+	for i, key := range ecsFieldPriority {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return i
+		}
+	}
+	return len(ecsFieldPriority)
+}
+
+// visitStructuredFields reports the sorted list of fields as objects, by
+// combining fields with a common prefix into objects.
+func visitStructuredFields(fields []keyValue, v diag.Visitor) error {
+	var path []string
+	for _, fld := range fields {
+		segments := strings.Split(fld.key, ".")
+		parents := segments[:len(segments)-1]
+
+		common := 0
+		for common < len(path) && common < len(parents) && path[common] == parents[common] {
+			common++
+		}
+		for ; len(path) > common; path = path[:len(path)-1] {
+			if err := v.OnObjEnd(); err != nil {
+				return err
+			}
+		}
+		for _, seg := range parents[common:] {
+			if err := v.OnObjStart(seg); err != nil {
+				return err
+			}
+			path = append(path, seg)
+		}
+
+		if err := v.OnValue(segments[len(segments)-1], fld.value); err != nil {
+			return err
+		}
+	}
+
+	for range path {
+		if err := v.OnObjEnd(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// foldSorted serializes a Go value with all object keys being sorted. The
+// value is converted into a generic representation first. Values nested
+// deeper than maxDepth are replaced by the truncation marker, such that
+// self-referencing values can be converted.
+func foldSorted(v structform.Visitor, value interface{}, opts []gotype.FoldOption, maxDepth int) error {
+	var tmp interface{}
+	u, err := gotype.NewUnfolder(&tmp)
+	if err != nil {
+		return err
+	}
+	limit := newLimitVisitor(u, Limits{MaxDepth: maxDepth}, nil)
+	it, err := gotype.NewIterator(limit, opts...)
+	if err != nil {
+		return err
+	}
+	if err := it.Fold(value); err != nil {
+		if err != errMaxDepth {
+			return err
+		}
+		if err := limit.recover(0); err != nil {
+			return err
+		}
+	}
+	return writeSorted(v, tmp, opts)
+}
+
+func writeSorted(v structform.Visitor, value interface{}, opts []gotype.FoldOption) error {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+
+		if err := v.OnObjectStart(len(keys), structform.AnyType); err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := v.OnKey(k.String()); err != nil {
+				return err
+			}
+			if err := writeSorted(v, rv.MapIndex(k).Interface(), opts); err != nil {
+				return err
+			}
+		}
+		return v.OnObjectFinished()
+
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+
+		if err := v.OnArrayStart(rv.Len(), structform.AnyType); err != nil {
+			return err
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := writeSorted(v, rv.Index(i).Interface(), opts); err != nil {
+				return err
+			}
+		}
+		return v.OnArrayFinished()
+	}
+
+	it, err := gotype.NewIterator(v, opts...)
+	if err != nil {
+		return err
+	}
+	return it.Fold(value)
+}
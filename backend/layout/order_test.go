@@ -0,0 +1,85 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/urso/sderr"
+)
+
+func TestSortFields(t *testing.T) {
+	cases := map[string]struct {
+		order FieldOrder
+		keys  []string // keys in the expected order of appearance
+	}{
+		"lexicographic": {
+			order: OrderLexicographic,
+			keys:  []string{`"fields"`, `"a":`, `"b":`, `"log"`, `"message"`},
+		},
+		"ecs": {
+			order: OrderECS,
+			keys:  []string{`"log"`, `"message"`, `"fields"`, `"a":`, `"b":`},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("hello", "m", map[string]int{"b": 2, "a": 1})
+			for i := 0; i < 10; i++ {
+				out := logString(t, JSON(nil, SortFields(test.order)), msg)
+				assertKeyOrder(t, out, test.keys)
+			}
+		})
+	}
+}
+
+func TestSortFieldsErrorContext(t *testing.T) {
+	cases := map[string]struct {
+		order FieldOrder
+		keys  []string
+	}{
+		"default":       {OrderDefault, []string{`"ctx"`, `"cause"`, `"message":"outer`}},
+		"lexicographic": {OrderLexicographic, []string{`"cause"`, `"ctx"`, `"message":"outer`}},
+		"ecs":           {OrderECS, []string{`"cause"`, `"ctx"`, `"message":"outer`}},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("failed")
+			// the context is reported within the error object for the
+			// errors of a multi-error only.
+			msg.Causes = []error{sderr.WrapAll([]error{
+				sderr.With("id", 1).Wrap(errors.New("inner"), "outer"),
+				errors.New("other"),
+			}, "failed")}
+
+			out := logString(t, JSON(nil, SortFields(test.order)), msg)
+			assertKeyOrder(t, out, test.keys)
+		})
+	}
+}
+
+func assertKeyOrder(t *testing.T, out string, keys []string) {
+	t.Helper()
+
+	pos := -1
+	for _, key := range keys {
+		i := strings.Index(out, key)
+		if i < 0 {
+			t.Fatalf("missing %v in %v", key, out)
+		}
+		if i < pos {
+			t.Fatalf("%v is out of order in %v", key, out)
+		}
+		pos = i
+	}
+}
@@ -0,0 +1,458 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+)
+
+type patternLayout struct {
+	out      io.Writer
+	buf      bytes.Buffer
+	tmp      bytes.Buffer
+	ops      []patternOp
+	withCtx  bool
+	redact   *redactor
+	filter   *fieldFilter
+	encoders valueEncoders
+	limits   Limits
+	escape   bool
+}
+
+// patternOp is a compiled conversion specifier or literal text of a pattern.
+type patternOp struct {
+	literal string
+	conv    *patternConversion
+	arg     string
+	mod     patternModifier
+}
+
+// patternConversion defines a conversion supported by the pattern layout.
+type patternConversion struct {
+	names    []string
+	fn       patternConverter
+	context  bool               // conversion requires the log context
+	validate func(string) error // validate the optional argument
+}
+
+// patternModifier configures padding and truncation of a conversion.
+type patternModifier struct {
+	min, max    int
+	leftAlign   bool
+	truncateEnd bool
+}
+
+type patternConverter func(l *patternLayout, buf *bytes.Buffer, op *patternOp, msg *backend.Message, ts time.Time)
+
+var patternConversions = []*patternConversion{
+	{names: []string{"d", "date"}, fn: convDate},
+	{names: []string{"p", "le", "level"}, fn: convLevel},
+	{names: []string{"c", "lo", "logger"}, fn: convLogger, validate: validateLoggerLength},
+	{names: []string{"F", "file"}, fn: convFile},
+	{names: []string{"path"}, fn: convPath},
+	{names: []string{"L", "line"}, fn: convLine},
+	{names: []string{"M", "func", "method"}, fn: convFunc},
+	{names: []string{"m", "msg", "message"}, fn: convMessage},
+	{names: []string{"ctx"}, fn: convContext, context: true},
+	{names: []string{"X", "mdc"}, fn: convField, context: true, validate: validateFieldName},
+	{names: []string{"err", "ex"}, fn: convErrors},
+	{names: []string{"n"}, fn: convNewline},
+}
+
+var patternConversionNames = map[string]*patternConversion{}
+
+func init() {
+	for _, conv := range patternConversions {
+		for _, name := range conv.names {
+			patternConversionNames[name] = conv
+		}
+	}
+}
+
+// Pattern creates a layout that formats events based on a conversion pattern,
+// similar to the pattern layouts found in log4j or logback.
+// The pattern is compiled once when calling Pattern. Errors in the pattern are
+// reported by the Factory.
+//
+// A conversion specifier has the form `%[-][min][.[-]max]name[{arg}]`.
+// The `min` width pads the output with spaces, right aligned by default, or
+// left aligned if `-` is given. The `max` width truncates the output from
+// the beginning, or from the end if `.-` is given. Use `%%` for a literal `%`.
+//
+// Supported conversions are:
+//
+//	%d, %date{layout}      Timestamp formatted using a Go time layout. Defaults to RFC3339.
+//	%p, %le, %level        Log level.
+//	%c, %lo, %logger{n}    Logger name. Segments are abbreviated to fit n characters.
+//	%F, %file              File name of the caller.
+//	%path                  Full file path of the caller.
+//	%L, %line              Line number of the caller.
+//	%M, %func{full}        Function name of the caller. Use `full` to include the package path.
+//	%m, %msg, %message     Log message.
+//	%ctx                   All context fields as key=value pairs.
+//	%X{key}, %mdc{key}     Value of the context field key.
+//	%err, %ex              Error messages of all causes.
+//	%n                     Newline.
+//
+// Events are written as formatted. Appenders do not add delimiters, such that
+// patterns should end with %n.
+//
+// For example:
+//
+//	Pattern("%d{2006-01-02 15:04:05.000} %-5level [%logger{20}] %file:%line %func - %msg %ctx%n")
+func Pattern(pattern string, opts ...Option) Factory {
+	ops, err := compilePattern(pattern)
+
+	return func(out io.Writer) (Layout, error) {
+		if err != nil {
+			return nil, err
+		}
+
+		o, err := applyOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		l := &patternLayout{
+			out:      out,
+			ops:      ops,
+			redact:   o.redact,
+			filter:   o.filter,
+			encoders: o.encoders,
+			limits:   o.limits,
+			escape:   !o.rawText,
+		}
+		for i := range ops {
+			if ops[i].conv != nil && ops[i].conv.context {
+				l.withCtx = true
+			}
+		}
+		return l, nil
+	}
+}
+
+func compilePattern(pattern string) ([]patternOp, error) {
+	var ops []patternOp
+	var literal strings.Builder
+
+	flushLiteral := func() {
+		if literal.Len() > 0 {
+			ops = append(ops, patternOp{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c != '%' {
+			literal.WriteByte(c)
+			i++
+			continue
+		}
+
+		i++
+		if i < len(pattern) && pattern[i] == '%' {
+			literal.WriteByte('%')
+			i++
+			continue
+		}
+
+		var op patternOp
+		var err error
+		op, i, err = parsePatternSpec(pattern, i)
+		if err != nil {
+			return nil, err
+		}
+
+		flushLiteral()
+		ops = append(ops, op)
+	}
+	flushLiteral()
+
+	return ops, nil
+}
+
+func parsePatternSpec(pattern string, i int) (op patternOp, pos int, err error) {
+	start := i - 1
+
+	// modifiers
+	if i < len(pattern) && pattern[i] == '-' {
+		op.mod.leftAlign = true
+		i++
+	}
+	op.mod.min, i = parsePatternInt(pattern, i)
+	if i < len(pattern) && pattern[i] == '.' {
+		i++
+		if i < len(pattern) && pattern[i] == '-' {
+			op.mod.truncateEnd = true
+			i++
+		}
+
+		digits := i
+		op.mod.max, i = parsePatternInt(pattern, i)
+		if i == digits {
+			return op, i, fmt.Errorf("missing maximum width in pattern at %v", start)
+		}
+	}
+
+	// conversion name
+	nameStart := i
+	for i < len(pattern) && isPatternLetter(pattern[i]) {
+		i++
+	}
+	name := pattern[nameStart:i]
+	if name == "" {
+		return op, i, fmt.Errorf("missing conversion name in pattern at %v", start)
+	}
+
+	conv, exists := patternConversionNames[name]
+	if !exists {
+		return op, i, fmt.Errorf("unknown conversion '%v' in pattern at %v", name, start)
+	}
+	op.conv = conv
+
+	// optional argument
+	if i < len(pattern) && pattern[i] == '{' {
+		end := strings.IndexByte(pattern[i:], '}')
+		if end < 0 {
+			return op, i, fmt.Errorf("missing '}' in pattern at %v", start)
+		}
+		op.arg = pattern[i+1 : i+end]
+		i += end + 1
+	}
+
+	if conv.validate != nil {
+		if err := conv.validate(op.arg); err != nil {
+			return op, i, fmt.Errorf("invalid argument for '%v' in pattern at %v: %v", name, start, err)
+		}
+	}
+
+	return op, i, nil
+}
+
+func validateLoggerLength(arg string) error {
+	if arg == "" {
+		return nil
+	}
+	_, err := strconv.Atoi(arg)
+	return err
+}
+
+func validateFieldName(arg string) error {
+	if arg == "" {
+		return errors.New("missing field name")
+	}
+	return nil
+}
+
+func parsePatternInt(pattern string, i int) (int, int) {
+	n := 0
+	for i < len(pattern) && pattern[i] >= '0' && pattern[i] <= '9' {
+		n = n*10 + int(pattern[i]-'0')
+		i++
+	}
+	return n, i
+}
+
+func isPatternLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func (l *patternLayout) UseContext() bool {
+	return l.withCtx
+}
+
+func (l *patternLayout) Log(msg backend.Message) {
+	defer func() {
+		if l.buf.Len()+l.buf.Cap() > persistentTextBufferSize {
+			l.buf = bytes.Buffer{}
+		} else {
+			l.buf.Reset()
+		}
+	}()
+
+	ts := eventTime(msg)
+	for i := range l.ops {
+		op := &l.ops[i]
+		if op.conv == nil {
+			l.buf.WriteString(op.literal)
+			continue
+		}
+
+		if op.mod.min == 0 && op.mod.max == 0 {
+			op.conv.fn(l, &l.buf, op, &msg, ts)
+			continue
+		}
+
+		l.tmp.Reset()
+		op.conv.fn(l, &l.tmp, op, &msg, ts)
+		op.mod.write(&l.buf, l.tmp.String())
+	}
+
+	l.out.Write(l.buf.Bytes())
+}
+
+func (m *patternModifier) write(buf *bytes.Buffer, s string) {
+	n := utf8.RuneCountInString(s)
+	if m.max > 0 && n > m.max {
+		if m.truncateEnd {
+			s = s[:runeOffset(s, m.max)]
+		} else {
+			s = s[runeOffset(s, n-m.max):]
+		}
+		n = m.max
+	}
+
+	pad := m.min - n
+	if pad > 0 && !m.leftAlign {
+		writePadding(buf, pad)
+	}
+	buf.WriteString(s)
+	if pad > 0 && m.leftAlign {
+		writePadding(buf, pad)
+	}
+}
+
+func runeOffset(s string, n int) int {
+	i := 0
+	for pos := range s {
+		if i == n {
+			return pos
+		}
+		i++
+	}
+	return len(s)
+}
+
+func writePadding(buf *bytes.Buffer, n int) {
+	for ; n > 0; n-- {
+		buf.WriteByte(' ')
+	}
+}
+
+func convDate(_ *patternLayout, buf *bytes.Buffer, op *patternOp, _ *backend.Message, ts time.Time) {
+	layout := op.arg
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	buf.WriteString(ts.Format(layout))
+}
+
+func convLevel(_ *patternLayout, buf *bytes.Buffer, _ *patternOp, msg *backend.Message, _ time.Time) {
+	buf.WriteString(levelString(msg.Level))
+}
+
+func convLogger(l *patternLayout, buf *bytes.Buffer, op *patternOp, msg *backend.Message, _ time.Time) {
+	name := escapeText(msg.Name, l.escape)
+	if op.arg == "" {
+		buf.WriteString(name)
+		return
+	}
+
+	n, _ := strconv.Atoi(op.arg)
+	buf.WriteString(abbreviateName(name, n))
+}
+
+func convFile(_ *patternLayout, buf *bytes.Buffer, _ *patternOp, msg *backend.Message, _ time.Time) {
+	buf.WriteString(filepath.Base(msg.Caller.File()))
+}
+
+func convPath(_ *patternLayout, buf *bytes.Buffer, _ *patternOp, msg *backend.Message, _ time.Time) {
+	buf.WriteString(msg.Caller.File())
+}
+
+func convLine(_ *patternLayout, buf *bytes.Buffer, _ *patternOp, msg *backend.Message, _ time.Time) {
+	buf.WriteString(strconv.Itoa(msg.Caller.Line()))
+}
+
+func convFunc(_ *patternLayout, buf *bytes.Buffer, op *patternOp, msg *backend.Message, _ time.Time) {
+	fn := msg.Caller.Function()
+	if op.arg != "full" {
+		if idx := strings.LastIndexByte(fn, '/'); idx >= 0 {
+			fn = fn[idx+1:]
+		}
+	}
+	buf.WriteString(fn)
+}
+
+func convMessage(l *patternLayout, buf *bytes.Buffer, _ *patternOp, msg *backend.Message, _ time.Time) {
+	buf.WriteString(escapeText(l.redact.string(msg.Message), l.escape))
+}
+
+func convContext(l *patternLayout, buf *bytes.Buffer, _ *patternOp, msg *backend.Message, _ time.Time) {
+	l.filter.context(l.redact.context(msg.Context)).VisitKeyValues(&textCtxPrinter{
+		buf:      buf,
+		encoders: l.encoders,
+		limits:   l.limits,
+		escape:   l.escape,
+	})
+}
+
+func convField(l *patternLayout, buf *bytes.Buffer, op *patternOp, msg *backend.Message, _ time.Time) {
+	ctx := l.filter.context(l.redact.context(msg.Context))
+	ctx.VisitKeyValues(visitValues(func(key string, v diag.Value) error {
+		if key != op.arg {
+			return nil
+		}
+
+		v.Reporter.Ifc(&v, func(value interface{}) {
+			if str, err := l.encoders.encodeString(value, l.limits); err == nil {
+				buf.WriteString(escapeText(str, l.escape))
+			}
+		})
+		return errStopVisit
+	}))
+}
+
+func convErrors(l *patternLayout, buf *bytes.Buffer, _ *patternOp, msg *backend.Message, _ time.Time) {
+	written := 0
+	for _, err := range msg.Causes {
+		if err == nil {
+			continue
+		}
+		if written > 0 {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(escapeText(l.redact.string(err.Error()), l.escape))
+		written++
+	}
+}
+
+func convNewline(_ *patternLayout, buf *bytes.Buffer, _ *patternOp, _ *backend.Message, _ time.Time) {
+	buf.WriteByte('\n')
+}
+
+// abbreviateName shortens the segments of a dotted name to their first
+// character, starting with the leftmost segment, until the name fits into n
+// characters. The last segment is never abbreviated.
+func abbreviateName(name string, n int) string {
+	if len(name) <= n {
+		return name
+	}
+
+	segments := strings.Split(name, ".")
+	total := len(name)
+	for i := 0; i < len(segments)-1 && total > n; i++ {
+		if len(segments[i]) > 1 {
+			total -= len(segments[i]) - 1
+			segments[i] = segments[i][:1]
+		}
+	}
+	return strings.Join(segments, ".")
+}
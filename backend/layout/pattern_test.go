@@ -0,0 +1,105 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPattern(t *testing.T) {
+	msg := testMessage("hello world", "id", 42, "user", "jane")
+	msg.Name = "ecslog.backend.layout"
+	msg.Timestamp = time.Date(2020, 3, 14, 15, 9, 26, 0, time.UTC)
+	msg.Causes = []error{errors.New("first"), errors.New("second")}
+
+	cases := []struct {
+		pattern string
+		want    string
+	}{
+		{"%msg", "hello world"},
+		{"%d{2006-01-02 15:04:05}", "2020-03-14 15:09:26"},
+		{"%level|%p|%le", "INFO|INFO|INFO"},
+		{"%logger", "ecslog.backend.layout"},
+		{"%c{15}", "e.b.layout"},
+		{"%X{id} %mdc{user} [%X{missing}]", "42 jane []"},
+		{"%err", "first; second"},
+		{"100%% %m%n", "100% hello world\n"},
+		{"[%8msg]", "[hello world]"},
+		{"[%14msg]", "[   hello world]"},
+		{"[%-14msg]", "[hello world   ]"},
+		{"[%.5msg]", "[world]"},
+		{"[%.-5msg]", "[hello]"},
+		{"[%-8.-5msg]", "[hello   ]"},
+		{"[%3.5level]", "[INFO]"},
+	}
+
+	for _, test := range cases {
+		got := logString(t, Pattern(test.pattern), msg)
+		if got != test.want {
+			t.Errorf("pattern %q: got %q, want %q", test.pattern, got, test.want)
+		}
+	}
+}
+
+func TestPatternContext(t *testing.T) {
+	cases := map[string]bool{
+		"%msg":       false,
+		"%msg %ctx":  true,
+		"%X{id} %m":  true,
+		"%d %p %err": false,
+	}
+	for pattern, withCtx := range cases {
+		l, err := Pattern(pattern)(&bytes.Buffer{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.UseContext() != withCtx {
+			t.Errorf("pattern %q: expected UseContext %v", pattern, withCtx)
+		}
+	}
+}
+
+func TestPatternErrors(t *testing.T) {
+	patterns := []string{
+		"%unknown",
+		"%",
+		"%-5",
+		"%5.msg",
+		"%d{2006",
+		"%X",
+		"%X{}",
+		"%logger{abc}",
+	}
+	for _, pattern := range patterns {
+		if _, err := Pattern(pattern)(&bytes.Buffer{}); err == nil {
+			t.Errorf("expected error for pattern %q", pattern)
+		}
+	}
+}
+
+func TestAbbreviateName(t *testing.T) {
+	cases := []struct {
+		name string
+		n    int
+		want string
+	}{
+		{"main", 0, "main"},
+		{"ecslog.backend.layout", 30, "ecslog.backend.layout"},
+		{"ecslog.backend.layout", 20, "e.backend.layout"},
+		{"ecslog.backend.layout", 10, "e.b.layout"},
+		{"ecslog.backend.layout", 1, "e.b.layout"},
+		{"a.b.layout", 5, "a.b.layout"},
+	}
+	for _, test := range cases {
+		if got := abbreviateName(test.name, test.n); got != test.want {
+			t.Errorf("abbreviateName(%q, %v): got %q, want %q", test.name, test.n, got, test.want)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/urso/diag"
@@ -20,25 +21,64 @@ import (
 )
 
 type textLayout struct {
-	out     io.Writer
-	buf     bytes.Buffer
-	withCtx bool
+	out      io.Writer
+	buf      bytes.Buffer
+	withCtx  bool
+	errCtx   bool
+	redact   *redactor
+	filter   *fieldFilter
+	encoders valueEncoders
+	colors   ColorScheme
+	order    FieldOrder
+	limits   Limits
+	escape   bool
 }
 
 type textCtxPrinter struct {
-	buf *bytes.Buffer
-	n   int
+	buf      *bytes.Buffer
+	prefix   string // written before the first key
+	keyColor string
+	encoders valueEncoders
+	limits   Limits
+	escape   bool // escape control characters in keys and unquoted values
+	depth    int  // nesting level of the value being printed
+	n        int
 }
 
 // maximum logger buffer size to keep in between calls
 const persistentTextBufferSize = 512
 
-func Text(withCtx bool) Factory {
+func Text(withCtx bool, opts ...Option) Factory {
 	return func(out io.Writer) (Layout, error) {
-		return &textLayout{
-			out:     out,
-			withCtx: withCtx,
-		}, nil
+		o, err := applyOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		l := &textLayout{
+			out:      out,
+			withCtx:  withCtx,
+			errCtx:   o.errCtx,
+			redact:   o.redact,
+			filter:   o.filter,
+			encoders: o.encoders,
+			order:    o.order,
+			limits:   o.limits,
+			escape:   !o.rawText,
+		}
+		if o.colors != nil && useColors(out) {
+			l.colors = *o.colors
+		}
+		return l, nil
+	}
+}
+
+// ErrorContext configures the text layout to print the diagnostic context of
+// errors, even if the layout has been created without context support.
+func ErrorContext() Option {
+	return func(o *options) error {
+		o.errCtx = true
+		return nil
 	}
 }
 
@@ -55,68 +95,78 @@ func (l *textLayout) Log(msg backend.Message) {
 		}
 	}()
 
-	ts := time.Now()
+	ts := eventTime(msg)
 
-	l.buf.WriteString(ts.Format(time.RFC3339))
+	writeColored(&l.buf, l.colors.Timestamp, ts.Format(time.RFC3339))
 	l.buf.WriteByte(' ')
-	l.buf.WriteString(l.level(msg.Level))
+	writeColored(&l.buf, l.colors.level(msg.Level), l.level(msg.Level))
 	l.buf.WriteByte('\t')
 	if msg.Name != "" {
-		fmt.Fprintf(&l.buf, "'%v' - ", msg.Name)
+		fmt.Fprintf(&l.buf, "'%v' - ", escapeText(msg.Name, l.escape))
 	}
 
 	caller := msg.Caller
-	fmt.Fprintf(&l.buf, "%v:%d", filepath.Base(caller.File()), caller.Line())
+	writeColored(&l.buf, l.colors.Caller, fmt.Sprintf("%v:%d", filepath.Base(caller.File()), caller.Line()))
 	l.buf.WriteByte('\t')
-	l.buf.WriteString(msg.Message)
+	l.buf.WriteString(escapeText(l.limitString(l.redact.string(msg.Message)), l.escape))
 
-	msg.Context.VisitKeyValues(&textCtxPrinter{buf: &l.buf})
+	visitOrdered(l.filter.context(l.redact.context(msg.Context)), l.order, false, l.ctxPrinter())
 	l.buf.WriteRune('\n')
 
-	// write errors
-	switch len(msg.Causes) {
-	case 0:
-		// do nothing
-
-	case 1:
-		if ioErr := l.OnErrorValue(msg.Causes[0], "\t"); ioErr != nil {
-			return
-		}
+	if ioErr := l.writeErrors(msg.Causes, "\t"); ioErr != nil {
+		return
+	}
 
-	case 2:
-		written := 0
-		l.buf.WriteString("\tcaused by:\n")
-		for _, err := range msg.Causes {
-			if err == nil {
-				continue
-			}
+	if max := l.limits.MaxEventSize; max > 0 && l.buf.Len() > max {
+		event, _ := truncateString(l.buf.String(), max)
+		l.buf.Reset()
+		l.buf.WriteString(event)
+		l.buf.WriteRune('\n')
+	}
 
-			if written != 0 {
-				l.buf.WriteString("\tand\n")
-			}
+	l.out.Write(l.buf.Bytes())
+}
 
-			written++
-			if ioErr := l.OnErrorValue(err, "\t    "); ioErr != nil {
-				return
-			}
+// writeErrors prints the tree of errors. Each line is prefixed with indent.
+// Nil errors are ignored.
+func (l *textLayout) writeErrors(errs []error, indent string) error {
+	var causes []error
+	for _, err := range errs {
+		if err != nil {
+			causes = append(causes, err)
 		}
 	}
 
-	l.out.Write(l.buf.Bytes())
+	path := newErrPath(l.limits.MaxErrorDepth)
+	switch len(causes) {
+	case 0:
+		return nil
+	case 1:
+		return l.OnErrorValue(causes[0], indent, indent, path)
+	default:
+		return l.onErrorBranches(causes, indent, path)
+	}
 }
 
-func (l *textLayout) OnErrorValue(err error, indent string) error {
-	l.buf.WriteString(indent)
+// OnErrorValue prints err and its causes. The first line is prefixed with
+// first, all following lines, including causes, are prefixed with indent.
+// Linear chains of causes are printed with the same indentation, while
+// the causes of multi-errors are printed as branches of a tree.
+func (l *textLayout) OnErrorValue(err error, first, indent string, parent *errPath) error {
+	path := parent.push(err)
+	l.buf.WriteString(first)
 
 	if file, line := sderr.At(err); file != "" {
-		fmt.Fprintf(&l.buf, "%v:%v\t", filepath.Base(file), line)
+		writeColored(&l.buf, l.colors.Caller, fmt.Sprintf("%v:%v", filepath.Base(file), line))
+		l.buf.WriteByte('\t')
 	}
 
-	l.buf.WriteString(err.Error())
+	errMsg := escapeText(l.limitString(l.redact.string(err.Error())), l.escape)
+	writeColored(&l.buf, l.colors.Cause, strings.Replace(errMsg, "\n", "\n"+indent, -1))
 
-	if l.withCtx {
-		if ctx := sderr.Context(err); ctx.Len() > 0 {
-			ctx.VisitKeyValues(&textCtxPrinter{buf: &l.buf})
+	if l.withCtx || l.errCtx {
+		if ctx := l.filter.context(l.redact.context(sderr.Context(err))); ctx.Len() > 0 {
+			ctx.VisitKeyValues(l.ctxPrinter())
 		}
 	}
 
@@ -124,38 +174,71 @@ func (l *textLayout) OnErrorValue(err error, indent string) error {
 		return ioErr
 	}
 
-	n := sderr.NumCauses(err)
+	n := errNumCauses(err)
 	switch n {
 	case 0:
 		// do nothing
 	case 1:
-		cause := sderr.Unwrap(err)
-		if cause != nil {
-			return l.OnErrorValue(cause, indent)
+		cause := errUnwrap(err)
+		if path.follow(cause) {
+			return l.OnErrorValue(cause, indent, indent, path)
 		}
 	default:
-		causeIndent := indent + "    "
-		written := 0
-		fmt.Fprintf(&l.buf, "%vmulti-error caused by:\n", indent)
+		var causes []error
 		for i := 0; i < n; i++ {
-			cause := sderr.Cause(err, i)
-			if cause != nil {
-				if written != 0 {
-					fmt.Fprintf(&l.buf, "%vand\n", indent)
-				}
-
-				written++
-				if err := l.OnErrorValue(cause, causeIndent); err != nil {
-					return err
-				}
+			if cause := errCause(err, i); path.follow(cause) {
+				causes = append(causes, cause)
 			}
 		}
+		if len(causes) > 0 {
+			return l.onErrorBranches(causes, indent, path)
+		}
+	}
+
+	if n > 0 && path.atLimit() {
+		l.buf.WriteString(indent)
+		l.buf.WriteString(truncatedMarker)
+		l.buf.WriteRune('\n')
 	}
+	return nil
+}
+
+// limitString truncates s to the configured maximum string length.
+func (l *textLayout) limitString(s string) string {
+	s, _ = truncateString(s, l.limits.MaxStringLen)
+	return s
+}
 
+func (l *textLayout) onErrorBranches(errs []error, indent string, path *errPath) error {
+	for i, err := range errs {
+		first, next := indent+"├─ ", indent+"│  "
+		if i == len(errs)-1 {
+			first, next = indent+"└─ ", indent+"   "
+		}
+
+		if ioErr := l.OnErrorValue(err, first, next, path); ioErr != nil {
+			return ioErr
+		}
+	}
 	return nil
 }
 
+func (l *textLayout) ctxPrinter() *textCtxPrinter {
+	return &textCtxPrinter{
+		buf:      &l.buf,
+		prefix:   "\t| ",
+		keyColor: l.colors.Key,
+		encoders: l.encoders,
+		limits:   l.limits,
+		escape:   l.escape,
+	}
+}
+
 func (_ *textLayout) level(lvl backend.Level) string {
+	return levelString(lvl)
+}
+
+func levelString(lvl backend.Level) string {
 	switch lvl {
 	case backend.Trace:
 		return "TRACE"
@@ -186,16 +269,7 @@ func (p *textCtxPrinter) OnObjEnd() error {
 func (p *textCtxPrinter) OnValue(key string, v diag.Value) (err error) {
 	p.onKey(key)
 	v.Reporter.Ifc(&v, func(value interface{}) {
-		switch v := value.(type) {
-		case *diag.Context:
-			p.buf.WriteRune('{')
-			err = v.VisitKeyValues(p)
-			p.buf.WriteRune('}')
-		case string, []byte:
-			fmt.Fprintf(p.buf, "%q", v)
-		default:
-			fmt.Fprintf(p.buf, "%v", v)
-		}
+		err = p.writeValue(value)
 	})
 
 	return err
@@ -205,9 +279,9 @@ func (p *textCtxPrinter) onKey(key string) error {
 	if p.n > 0 {
 		p.buf.WriteRune(' ')
 	} else {
-		p.buf.WriteString("\t| ")
+		p.buf.WriteString(p.prefix)
 	}
-	p.buf.WriteString(key)
+	writeColored(p.buf, p.keyColor, escapeText(key, p.escape))
 	p.buf.WriteRune('=')
 	p.n++
 	return nil
@@ -0,0 +1,152 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/urso/diag"
+	"github.com/urso/ecslog/backend"
+)
+
+// treeErr is a multi-error with its own message.
+type treeErr struct {
+	msg    string
+	causes []error
+}
+
+func (e *treeErr) Error() string   { return e.msg }
+func (e *treeErr) Unwrap() []error { return e.causes }
+
+// ctxErr is an error with diagnostic context.
+type ctxErr struct {
+	msg  string
+	ctx  *diag.Context
+	next error
+}
+
+func (e *ctxErr) Error() string          { return e.msg }
+func (e *ctxErr) Unwrap() error          { return e.next }
+func (e *ctxErr) Context() *diag.Context { return e.ctx }
+
+func TestTextErrorTree(t *testing.T) {
+	a, b, c, d := errors.New("a"), errors.New("b"), errors.New("c"), errors.New("d")
+
+	cases := map[string]struct {
+		causes []error
+		want   string
+	}{
+		"no cause": {
+			want: "",
+		},
+		"one cause": {
+			causes: []error{&chainErr{msg: "outer", next: a}},
+			want: "" +
+				"\touter\n" +
+				"\ta\n",
+		},
+		"two causes": {
+			causes: []error{a, b},
+			want: "" +
+				"\t├─ a\n" +
+				"\t└─ b\n",
+		},
+		"three causes": {
+			causes: []error{a, b, c},
+			want: "" +
+				"\t├─ a\n" +
+				"\t├─ b\n" +
+				"\t└─ c\n",
+		},
+		"nested multi-errors": {
+			causes: []error{&treeErr{"multi", []error{
+				&treeErr{"left", []error{a, &chainErr{msg: "wrapped", next: b}}},
+				&treeErr{"right", []error{c, d}},
+			}}},
+			want: "" +
+				"\tmulti\n" +
+				"\t├─ left\n" +
+				"\t│  ├─ a\n" +
+				"\t│  └─ wrapped\n" +
+				"\t│     b\n" +
+				"\t└─ right\n" +
+				"\t   ├─ c\n" +
+				"\t   └─ d\n",
+		},
+		"nested in cause list": {
+			causes: []error{&treeErr{"multi", []error{a, b}}, c},
+			want: "" +
+				"\t├─ multi\n" +
+				"\t│  ├─ a\n" +
+				"\t│  └─ b\n" +
+				"\t└─ c\n",
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("failed")
+			msg.Causes = test.causes
+
+			if got := textErrors(t, Text(false), msg); got != test.want {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTextErrorContext(t *testing.T) {
+	errCtx := diag.NewContext(nil, nil)
+	errCtx.AddAll("id", 42)
+	err := &ctxErr{msg: "outer", ctx: errCtx, next: errors.New("inner")}
+
+	cases := map[string]struct {
+		factory Factory
+		want    string
+	}{
+		"disabled": {
+			factory: Text(false),
+			want:    "\touter\n\tinner\n",
+		},
+		"enabled": {
+			factory: Text(false, ErrorContext()),
+			want:    "\touter\t| id=42\n\tinner\n",
+		},
+		"with context": {
+			factory: Text(true),
+			want:    "\touter\t| id=42\n\tinner\n",
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("failed")
+			msg.Causes = []error{err}
+
+			if got := textErrors(t, test.factory, msg); got != test.want {
+				t.Errorf("unexpected output:\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+// textErrors logs msg and returns the output following the first line,
+// which contains the timestamp.
+func textErrors(t *testing.T, factory Factory, msg backend.Message) string {
+	t.Helper()
+
+	out := logString(t, factory, msg)
+	idx := strings.IndexByte(out, '\n')
+	if idx < 0 {
+		t.Fatalf("missing newline in output: %q", out)
+	}
+	return out[idx+1:]
+}
@@ -0,0 +1,273 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+)
+
+type prettyLayout struct {
+	out      io.Writer
+	buf      bytes.Buffer
+	redact   *redactor
+	filter   *fieldFilter
+	encoders valueEncoders
+	limits   Limits
+	colors   ColorScheme
+	escape   bool
+	errors   *textLayout // renders the error tree
+}
+
+// prettyNode is a context field or nested object. The fields of an object are
+// collected before printing, so to align the values of all fields in an
+// object.
+type prettyNode struct {
+	key      string
+	value    string
+	children []*prettyNode
+	isObj    bool
+}
+
+// prettyTreeBuilder creates the tree of nodes from a context via
+// VisitStructured.
+type prettyTreeBuilder struct {
+	stack    []*prettyNode
+	encoders valueEncoders
+	limits   Limits
+	escape   bool
+}
+
+const prettyIndent = "    "
+
+// Pretty creates a multi-line layout for local development. The header line
+// is followed by the context fields, one field per line. Nested objects are
+// printed as indented trees, with the values of all fields in an object being
+// aligned. Errors and stack traces are printed as indented trees after the
+// context. Colors can be enabled via the Colors option.
+//
+// Control characters in messages, keys, and values are escaped like in the
+// text layout. With RawText, multi-line messages and values are printed
+// as is, with the continuation lines being indented.
+func Pretty(opts ...Option) Factory {
+	return func(out io.Writer) (Layout, error) {
+		o, err := applyOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		escape := !o.rawText
+		l := &prettyLayout{
+			out:      out,
+			redact:   o.redact,
+			filter:   o.filter,
+			encoders: o.encoders,
+			limits:   o.limits,
+			escape:   escape,
+			errors: &textLayout{
+				withCtx:  true,
+				redact:   o.redact,
+				filter:   o.filter,
+				encoders: o.encoders,
+				limits:   o.limits,
+				escape:   escape,
+			},
+		}
+		if o.colors != nil && useColors(out) {
+			l.colors = *o.colors
+			l.errors.colors = *o.colors
+		}
+		return l, nil
+	}
+}
+
+func (l *prettyLayout) UseContext() bool { return true }
+
+func (l *prettyLayout) Log(msg backend.Message) {
+	defer func() {
+		if l.buf.Len()+l.buf.Cap() > persistentTextBufferSize {
+			l.buf = bytes.Buffer{}
+		} else {
+			l.buf.Reset()
+		}
+	}()
+
+	ts := eventTime(msg)
+
+	writeColored(&l.buf, l.colors.Timestamp, ts.Format("2006-01-02 15:04:05.000"))
+	l.buf.WriteByte(' ')
+	writeColored(&l.buf, l.colors.level(msg.Level), fmt.Sprintf("%-5s", levelString(msg.Level)))
+	l.buf.WriteByte(' ')
+	if msg.Name != "" {
+		fmt.Fprintf(&l.buf, "[%v] ", escapeText(msg.Name, l.escape))
+	}
+	caller := msg.Caller
+	writeColored(&l.buf, l.colors.Caller, fmt.Sprintf("%v:%d", filepath.Base(caller.File()), caller.Line()))
+	l.buf.WriteByte(' ')
+	l.buf.WriteString(l.indentLines(l.redact.string(msg.Message), prettyIndent))
+	l.buf.WriteByte('\n')
+
+	if ctx := l.filter.context(l.redact.context(msg.Context)); ctx.Len() > 0 {
+		builder := &prettyTreeBuilder{
+			stack:    []*prettyNode{{isObj: true}},
+			encoders: l.encoders,
+			limits:   l.limits,
+			escape:   l.escape,
+		}
+		if err := ctx.VisitStructured(builder); err != nil {
+			return
+		}
+		l.writeNodes(builder.stack[0].children, prettyIndent)
+	}
+
+	l.errors.buf.Reset()
+	if err := l.errors.writeErrors(msg.Causes, prettyIndent+"  "); err != nil {
+		return
+	}
+	if l.errors.buf.Len() > 0 {
+		l.writeKey(prettyIndent, "error")
+		l.buf.WriteString(":\n")
+		l.buf.Write(l.errors.buf.Bytes())
+	}
+
+	for _, cause := range msg.Causes {
+		if cause == nil {
+			continue
+		}
+		if trace := formatStackTrace(errChainStackPCs(cause)); trace != "" {
+			l.writeKey(prettyIndent, "stack trace")
+			l.buf.WriteString(":\n")
+			indent := prettyIndent + "  "
+			l.buf.WriteString(indent)
+			l.buf.WriteString(strings.Replace(trace, "\n", "\n"+indent, -1))
+			l.buf.WriteByte('\n')
+		}
+	}
+
+	l.out.Write(l.buf.Bytes())
+}
+
+// writeNodes prints the fields of an object. The values of all fields are
+// aligned.
+func (l *prettyLayout) writeNodes(nodes []*prettyNode, indent string) {
+	width := 0
+	for _, node := range nodes {
+		if n := utf8.RuneCountInString(node.key); !node.isObj && n > width {
+			width = n
+		}
+	}
+
+	for _, node := range nodes {
+		l.writeKey(indent, node.key)
+		l.buf.WriteByte(':')
+
+		if node.isObj {
+			l.buf.WriteByte('\n')
+			l.writeNodes(node.children, indent+"  ")
+			continue
+		}
+
+		l.buf.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(node.key)+1))
+		l.buf.WriteString(strings.Replace(node.value, "\n", "\n"+indent+"  ", -1))
+		l.buf.WriteByte('\n')
+	}
+}
+
+func (l *prettyLayout) writeKey(indent, key string) {
+	l.buf.WriteString(indent)
+	writeColored(&l.buf, l.colors.Key, escapeText(key, l.escape))
+}
+
+// indentLines escapes s, or indents the continuation lines of s if escaping
+// is disabled.
+func (l *prettyLayout) indentLines(s, indent string) string {
+	if l.escape {
+		return escapeText(s, true)
+	}
+	return strings.Replace(s, "\n", "\n"+indent, -1)
+}
+
+func (b *prettyTreeBuilder) current() *prettyNode {
+	return b.stack[len(b.stack)-1]
+}
+
+func (b *prettyTreeBuilder) push(key string) {
+	node := &prettyNode{key: key, isObj: true}
+	parent := b.current()
+	parent.children = append(parent.children, node)
+	b.stack = append(b.stack, node)
+}
+
+func (b *prettyTreeBuilder) pop() {
+	b.stack = b.stack[:len(b.stack)-1]
+}
+
+func (b *prettyTreeBuilder) OnObjStart(key string) error {
+	b.push(key)
+	return nil
+}
+
+func (b *prettyTreeBuilder) OnObjEnd() error {
+	b.pop()
+	return nil
+}
+
+func (b *prettyTreeBuilder) OnValue(key string, v diag.Value) (err error) {
+	v.Reporter.Ifc(&v, func(value interface{}) {
+		switch val := value.(type) {
+		case *diag.Context:
+			b.push(key)
+			err = val.VisitStructured(b)
+			b.pop()
+
+		default:
+			var str string
+			if str, err = b.value(val); err == nil {
+				parent := b.current()
+				parent.children = append(parent.children, &prettyNode{key: key, value: str})
+			}
+		}
+	})
+	return err
+}
+
+// value formats a field value. Value encoders and marshalers are applied
+// like in the text layouts.
+func (b *prettyTreeBuilder) value(v interface{}) (string, error) {
+	if s, ok := b.encoders.encode(v).(string); ok {
+		return prettyValue(s, b.escape), nil
+	}
+	s, err := b.encoders.encodeString(v, b.limits)
+	return escapeText(s, b.escape), err
+}
+
+// prettyValue formats a string. Strings are quoted if they contain control
+// characters other than newlines, or leading or trailing spaces. If escape is
+// set, strings with newlines or backslashes are quoted as well.
+func prettyValue(s string, escape bool) string {
+	if s == "" || strings.TrimSpace(s) != s {
+		return strconv.Quote(s)
+	}
+	for _, r := range s {
+		if escape && (r == '\\' || isControl(r)) {
+			return strconv.Quote(s)
+		}
+		if r != '\n' && (r < ' ' || r == 0x7f || r == utf8.RuneError) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
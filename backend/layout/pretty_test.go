@@ -0,0 +1,86 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urso/sderr"
+)
+
+func TestPretty(t *testing.T) {
+	msg := testMessage("hello\nworld",
+		"id", 42,
+		"http.request.method", "GET",
+		"http.request.bytes", 10,
+		"padded", " x ",
+		"multi", "a\nb",
+	)
+	msg.Timestamp = time.Date(2020, 1, 2, 3, 4, 5, 6e6, time.Local)
+	msg.Causes = []error{errors.New("boom")}
+
+	want := strings.Join([]string{
+		"2020-01-02 03:04:05.006 INFO  [test] .:0 hello",
+		"    world",
+		"    http:",
+		"      request:",
+		"        bytes:  10",
+		"        method: GET",
+		"    id:     42",
+		"    multi:  a",
+		"      b",
+		`    padded: " x "`,
+		"    error:",
+		"      boom",
+		"",
+	}, "\n")
+
+	if got := logString(t, Pretty(RawText()), msg); got != want {
+		t.Errorf("unexpected output:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestPrettyStackTrace(t *testing.T) {
+	msg := testMessage("failed")
+	msg.Causes = []error{sderr.Wrap(sderr.WithStack().Errf("inner"), "outer")}
+
+	out := logString(t, Pretty(), msg)
+	if n := strings.Count(out, "stack trace:"); n != 1 {
+		t.Fatalf("expected one stack trace, got %v in:\n%v", n, out)
+	}
+	if !strings.Contains(out, "TestPrettyStackTrace") {
+		t.Errorf("stack trace does not include the test function:\n%v", out)
+	}
+}
+
+func TestPrettyValue(t *testing.T) {
+	cases := map[string]struct {
+		in     string
+		escape bool
+		want   string
+	}{
+		"string":         {"plain", false, "plain"},
+		"newline":        {"a\nb", false, "a\nb"},
+		"empty":          {"", false, `""`},
+		"spaces":         {" a", false, `" a"`},
+		"control":        {"a\tb", false, `"a\tb"`},
+		"invalid utf":    {"a\xffb", false, `"a\xffb"`},
+		"escaped string": {"plain", true, "plain"},
+		"escaped":        {"a\nb", true, `"a\nb"`},
+		"backslash":      {`a\nb`, true, `"a\\nb"`},
+		"raw backslash":  {`a\nb`, false, `a\nb`},
+		"c1":             {"a\u009bb", true, `"a\u009bb"`},
+	}
+	for name, test := range cases {
+		if got := prettyValue(test.in, test.escape); got != test.want {
+			t.Errorf("%v: got %q, want %q", name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	structform "github.com/elastic/go-structform"
+)
+
+// recursiveTypes caches the result of isRecursiveType.
+var recursiveTypes sync.Map // reflect.Type -> bool
+
+// isRecursiveType checks if t references itself, like linked lists or trees.
+// gotype can not build a folder for recursive types, such that these values
+// must be serialized via foldRecursive.
+func isRecursiveType(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	if v, ok := recursiveTypes.Load(t); ok {
+		return v.(bool)
+	}
+
+	recursive := typeReferences(t, map[reflect.Type]bool{})
+	recursiveTypes.Store(t, recursive)
+	return recursive
+}
+
+// typeReferences checks if t references a type in active, the types t is
+// nested in.
+func typeReferences(t reflect.Type, active map[reflect.Type]bool) bool {
+	if active[t] {
+		return true
+	}
+
+	active[t] = true
+	defer delete(active, t)
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return typeReferences(t.Elem(), active)
+	case reflect.Map:
+		return typeReferences(t.Key(), active) || typeReferences(t.Elem(), active)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if typeReferences(t.Field(i).Type, active) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// foldRecursive serializes a value via reflection. Struct fields are named
+// and omitted according to the `struct` tag, like in gotype. The function
+// relies on the limitVisitor to stop at the configured depth, such that
+// self-referencing values are reported up to the depth limit.
+func foldRecursive(v structform.Visitor, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return v.OnNil()
+
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return v.OnNil()
+		}
+		return foldRecursive(v, rv.Elem())
+
+	case reflect.Bool:
+		return v.OnBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.OnInt64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.OnUint64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.OnFloat64(rv.Float())
+	case reflect.String:
+		return v.OnString(rv.String())
+
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return v.OnNil()
+		}
+		if err := v.OnArrayStart(rv.Len(), structform.AnyType); err != nil {
+			return err
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := foldRecursive(v, rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return v.OnArrayFinished()
+
+	case reflect.Map:
+		if rv.IsNil() {
+			return v.OnNil()
+		}
+		if err := v.OnObjectStart(rv.Len(), structform.AnyType); err != nil {
+			return err
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			if err := v.OnKey(fmt.Sprint(iter.Key().Interface())); err != nil {
+				return err
+			}
+			if err := foldRecursive(v, iter.Value()); err != nil {
+				return err
+			}
+		}
+		return v.OnObjectFinished()
+
+	case reflect.Struct:
+		return foldRecursiveStruct(v, rv)
+
+	default:
+		return v.OnString(fmt.Sprint(rv))
+	}
+}
+
+func foldRecursiveStruct(v structform.Visitor, rv reflect.Value) error {
+	if err := v.OnObjectStart(-1, structform.AnyType); err != nil {
+		return err
+	}
+
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // not exported
+		}
+
+		name, omitEmpty := field.Name, false
+		if tag, ok := field.Tag.Lookup("struct"); ok {
+			if tag == "-" {
+				continue
+			}
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				omitEmpty = omitEmpty || opt == "omitempty"
+			}
+		}
+
+		value := rv.Field(i)
+		if omitEmpty && value.IsZero() {
+			continue
+		}
+
+		if err := v.OnKey(name); err != nil {
+			return err
+		}
+		if err := foldRecursive(v, value); err != nil {
+			return err
+		}
+	}
+
+	return v.OnObjectFinished()
+}
@@ -0,0 +1,341 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/urso/diag"
+)
+
+// Redaction configures the replacement of sensitive values in log events.
+//
+// Fields whose flattened key matches one of the Keys patterns have their
+// value replaced as a whole. Keys are matched as they appear in the log
+// context or the context of an error value, without any namespace added by the
+// layout (e.g. `password`, not `fields.password`).
+//
+// String values, the log message, and error messages are additionally scanned
+// by the configured Detectors. Each match found is replaced.
+type Redaction struct {
+	// Keys is a list of glob patterns matched against flattened field keys.
+	// Each path segment is matched using path.Match, such that '*' matches
+	// any sequence of characters except '.'. The segment `**` matches any
+	// number of segments. For example `**.password` matches `password` and
+	// `db.password`.
+	Keys []string
+
+	// Detectors finds sensitive values within strings.
+	Detectors []Detector
+
+	// Mode selects how sensitive values are replaced.
+	Mode RedactMode
+
+	// HashKey is the secret key used by RedactHash. It is required for
+	// RedactHash, as an unkeyed hash of a short value like a credit card
+	// number can be reversed by brute force.
+	HashKey []byte
+}
+
+// RedactMode configures how a sensitive value is replaced.
+type RedactMode uint8
+
+const (
+	// RedactReplace replaces the value with `[REDACTED]`.
+	RedactReplace RedactMode = iota
+
+	// RedactHash replaces the value with the hex encoded HMAC-SHA256 of the
+	// value, keyed by HashKey. Hashed values can still be correlated between
+	// events using the same key.
+	RedactHash
+
+	// RedactMask masks all but the last 4 characters of the value.
+	RedactMask
+)
+
+// Detector finds sensitive substrings. It returns the start and end index of
+// each match in s, similar to (*regexp.Regexp).FindAllStringIndex.
+type Detector func(s string) [][]int
+
+var (
+	// DetectEmail finds e-mail addresses.
+	DetectEmail Detector = regexDetector(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+	// DetectJWT finds JSON Web Tokens.
+	DetectJWT Detector = regexDetector(`eyJ[A-Za-z0-9_\-]+\.eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+
+	// DetectCreditCard finds credit card numbers with 13 to 19 digits,
+	// optionally grouped by spaces or dashes. Candidates must pass the Luhn
+	// checksum.
+	DetectCreditCard Detector = creditCardDetector
+)
+
+const redactedValue = "[REDACTED]"
+
+type redactor struct {
+	keys      [][]string
+	detectors []Detector
+	mode      RedactMode
+	hashKey   []byte
+}
+
+var reCreditCard = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)
+
+// Redact configures the layout to replace sensitive values.
+func Redact(r Redaction) Option {
+	return func(o *options) error {
+		if r.Mode == RedactHash && len(r.HashKey) == 0 {
+			return errors.New("redaction mode hash requires a HashKey")
+		}
+
+		red := &redactor{
+			detectors: r.Detectors,
+			mode:      r.Mode,
+			hashKey:   r.HashKey,
+		}
+		for _, pattern := range r.Keys {
+			segments := strings.Split(pattern, ".")
+			for _, seg := range segments {
+				if _, err := path.Match(seg, ""); err != nil {
+					return fmt.Errorf("invalid redaction key pattern '%v': %v", pattern, err)
+				}
+			}
+			red.keys = append(red.keys, segments)
+		}
+
+		o.redact = red
+		return nil
+	}
+}
+
+func regexDetector(pattern string) Detector {
+	re := regexp.MustCompile(pattern)
+	return func(s string) [][]int {
+		return re.FindAllStringIndex(s, -1)
+	}
+}
+
+func creditCardDetector(s string) [][]int {
+	candidates := reCreditCard.FindAllStringIndex(s, -1)
+	matches := candidates[:0]
+	for _, m := range candidates {
+		if luhnValid(s[m[0]:m[1]]) {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// context returns a copy of ctx with all sensitive values replaced. The
+// original context is returned if no value needs to be replaced.
+func (r *redactor) context(ctx *diag.Context) *diag.Context {
+	if r == nil || ctx.Len() == 0 || !r.needsRedact("", ctx) {
+		return ctx
+	}
+
+	redacted := diag.NewContext(nil, nil)
+	r.copyFields(redacted, "", ctx.Standardized(), true)
+	r.copyFields(redacted, "", ctx.User(), false)
+	return redacted
+}
+
+// string replaces all sensitive values in s found by the detectors.
+func (r *redactor) string(s string) string {
+	if r == nil || len(r.detectors) == 0 {
+		return s
+	}
+
+	var matches [][]int
+	for _, detect := range r.detectors {
+		matches = append(matches, detect(s)...)
+	}
+	if len(matches) == 0 {
+		return s
+	}
+
+	var buf strings.Builder
+	end := 0
+	for _, m := range sortMatches(matches) {
+		if m[0] < end {
+			continue // overlaps with the last replaced match
+		}
+		buf.WriteString(s[end:m[0]])
+		buf.WriteString(r.replace(s[m[0]:m[1]]))
+		end = m[1]
+	}
+	buf.WriteString(s[end:])
+	return buf.String()
+}
+
+func (r *redactor) needsRedact(prefix string, ctx *diag.Context) bool {
+	found := false
+	ctx.VisitKeyValues(visitValues(func(key string, v diag.Value) error {
+		key = prefix + key
+		if r.matchKey(key) {
+			found = true
+		} else if nested, ok := v.Ifc.(*diag.Context); ok {
+			found = r.needsRedact(key+".", nested)
+		} else if str, ok := stringValue(v); ok {
+			found = r.string(str) != str
+		}
+
+		if found {
+			return errStopVisit
+		}
+		return nil
+	}))
+	return found
+}
+
+func (r *redactor) copyFields(to *diag.Context, prefix string, from *diag.Context, std bool) {
+	from.VisitKeyValues(visitValues(func(key string, v diag.Value) error {
+		to.AddField(diag.Field{
+			Key:          key,
+			Value:        r.value(prefix+key, v),
+			Standardized: std,
+		})
+		return nil
+	}))
+}
+
+func (r *redactor) value(key string, v diag.Value) diag.Value {
+	if r.matchKey(key) {
+		if r.mode == RedactReplace {
+			return diag.ValString(redactedValue)
+		}
+		return diag.ValString(r.replace(fmt.Sprint(v.Interface())))
+	}
+
+	if nested, ok := v.Ifc.(*diag.Context); ok {
+		if !r.needsRedact(key+".", nested) {
+			return v
+		}
+
+		redacted := diag.NewContext(nil, nil)
+		r.copyFields(redacted, key+".", nested.Standardized(), true)
+		r.copyFields(redacted, key+".", nested.User(), false)
+		return diag.ValAny(redacted)
+	}
+
+	if str, ok := stringValue(v); ok {
+		if s := r.string(str); s != str {
+			return diag.ValString(s)
+		}
+	}
+	return v
+}
+
+func stringValue(v diag.Value) (string, bool) {
+	if v.Reporter.Type() == diag.StringType {
+		return v.String, true
+	}
+	str, ok := v.Ifc.(string)
+	return str, ok
+}
+
+func (r *redactor) replace(s string) string {
+	switch r.mode {
+	case RedactHash:
+		mac := hmac.New(sha256.New, r.hashKey)
+		mac.Write([]byte(s))
+		return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))
+
+	case RedactMask:
+		n := utf8.RuneCountInString(s)
+		keep := 4
+		if n <= keep {
+			return strings.Repeat("*", n)
+		}
+
+		i := 0
+		for pos := range s {
+			if i == n-keep {
+				return strings.Repeat("*", n-keep) + s[pos:]
+			}
+			i++
+		}
+		return s
+
+	default:
+		return redactedValue
+	}
+}
+
+func (r *redactor) matchKey(key string) bool {
+	if len(r.keys) == 0 {
+		return false
+	}
+
+	segments := strings.Split(key, ".")
+	for _, pattern := range r.keys {
+		if matchSegments(pattern, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+func sortMatches(matches [][]int) [][]int {
+	// insertion sort, we expect only a very small number of matches
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0 && matches[j][0] < matches[j-1][0]; j-- {
+			matches[j], matches[j-1] = matches[j-1], matches[j]
+		}
+	}
+	return matches
+}
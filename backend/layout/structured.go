@@ -9,6 +9,7 @@ package layout
 import (
 	"bytes"
 	"io"
+	"time"
 
 	"github.com/urso/diag"
 	"github.com/urso/diag-ecs/ecs"
@@ -25,20 +26,42 @@ import (
 type structLayout struct {
 	out         io.Writer
 	buf         bytes.Buffer
+	frame       []byte // buffer for framing events
+	framing     Framing
 	fields      *diag.Context
 	makeEncoder func(io.Writer) structform.Visitor
 	types       *gotype.Iterator
 	typeOpts    []gotype.FoldOption
 	visitor     structform.Visitor
+	redact      *redactor
+	filter      *fieldFilter
+	encoders    valueEncoders
+	rootErrType bool
+	userFields  *userFieldsMapping
+	flattenSep  string
+	order       FieldOrder
+	limits      Limits
+	limit       *limitVisitor
+	bulk        *bulkIndex
+
+	eventTimestamp bool
+	ecsVersion     string
+	originFunction bool
+	relPaths       bool
 }
 
 type structVisitor structLayout
 
+// logTruncated marks events with values being truncated due to the
+// configured Limits.
+var logTruncated = diag.Field{Key: "log.truncated", Value: diag.ValBool(true), Standardized: true}
+
 // errorVal is used to wrap errors, so to notify encoding callback that
 // we're dealing with special error value who's context doesn't need to be
 // reported.
 type errorVal struct {
-	err error
+	err  error
+	path *errPath
 }
 
 // multiErrOf is used to wrap a multierror, so to notify the encoding
@@ -46,54 +69,110 @@ type errorVal struct {
 // Each error in the multierror must be deal separately, creating and reporting
 // it's local context.
 type multiErrOf struct {
-	err error
+	err  error
+	path *errPath
 }
 
 type multiErr struct {
 	errs []error
 }
 
-func JSON(fields []diag.Field, opts ...gotype.FoldOption) Factory {
+// JSON creates a layout writing one JSON document per event. Fold options
+// are passed via FoldOptions.
+func JSON(fields []diag.Field, opts ...Option) Factory {
 	return Structured(func(w io.Writer) structform.Visitor {
 		return json.NewVisitor(w)
 	}, fields, opts...)
 }
 
-func UBJSON(fields []diag.Field, opts ...gotype.FoldOption) Factory {
+// UBJSON creates a layout writing one UBJSON document per event. Events are
+// written back to back without framing by default, like in earlier versions,
+// as readers of existing streams expect plain UBJSON documents. Use
+// Framed(FrameUint32) or Framed(FrameVarint) for streams that must be read
+// with the frame reader.
+func UBJSON(fields []diag.Field, opts ...Option) Factory {
 	return Structured(func(w io.Writer) structform.Visitor {
 		return ubjson.NewVisitor(w)
-	}, fields, opts...)
+	}, fields, append([]Option{Framed(FrameNone)}, opts...)...)
 }
 
-func CBOR(fields []diag.Field, opts ...gotype.FoldOption) Factory {
+func CBOR(fields []diag.Field, opts ...Option) Factory {
 	return Structured(func(w io.Writer) structform.Visitor {
 		return cborl.NewVisitor(w)
-	}, fields, opts...)
+	}, fields, append([]Option{Framed(FrameCBORSequence)}, opts...)...)
 }
 
+// Structured creates a layout encoding events via the visitor returned by
+// makeEncoder. Fold options for values of unknown type, passed directly in
+// earlier versions, are now configured via FoldOptions.
 func Structured(
 	makeEncoder func(io.Writer) structform.Visitor,
 	fields []diag.Field,
-	opts ...gotype.FoldOption,
+	opts ...Option,
 ) Factory {
 	return func(out io.Writer) (Layout, error) {
+		o, err := applyOptions(append([]Option{Framed(FrameNewline)}, opts...))
+		if err != nil {
+			return nil, err
+		}
+
 		logCtx := diag.NewContext(nil, nil)
 		logCtx.AddFields(fields...)
 
 		l := &structLayout{
 			out:         out,
+			framing:     o.framing,
 			fields:      logCtx,
 			makeEncoder: makeEncoder,
-			typeOpts:    opts,
+			typeOpts:    o.foldOpts,
+			redact:      o.redact,
+			filter:      o.filter,
+			encoders:    o.encoders,
+			rootErrType: o.rootErrType,
+			userFields:  newUserFieldsMapping(o),
+			flattenSep:  o.flattenSep,
+			order:       o.order,
+			limits:      o.limits,
+			bulk:        o.bulk,
+
+			eventTimestamp: o.eventTimestamp,
+			ecsVersion:     o.ecsVersion,
+			originFunction: o.originFunction,
+			relPaths:       o.relPaths,
 		}
 		l.reset()
 		return l, nil
 	}
 }
 
+// FoldOptions configures the go-structform type folding used to serialize
+// values of unknown type.
+func FoldOptions(opts ...gotype.FoldOption) Option {
+	return func(o *options) error {
+		o.foldOpts = append(o.foldOpts, opts...)
+		return nil
+	}
+}
+
+// RootErrorType configures the structured layout to report the type of the
+// wrapped cause in `error.type`, if an error has been created by sderr.
+// By default the type of the error value passed to the logger is reported.
+func RootErrorType() Option {
+	return func(o *options) error {
+		o.rootErrType = true
+		return nil
+	}
+}
+
 func (l *structLayout) reset() {
 	l.buf.Reset()
 	visitor := l.makeEncoder(&l.buf)
+	if l.flattenSep != "" {
+		visitor = newFlattenVisitor(visitor, l.flattenSep)
+	}
+	l.limit = newLimitVisitor(visitor, l.limits, l.buf.Len)
+	l.limit.fieldsOnly, l.limit.base = true, -1
+	visitor = l.limit
 	l.types, _ = gotype.NewIterator(visitor, l.typeOpts...)
 	l.visitor = visitor
 }
@@ -101,30 +180,96 @@ func (l *structLayout) reset() {
 func (l *structLayout) UseContext() bool { return true }
 
 func (l *structLayout) Log(msg backend.Message) {
-	var userCtx, stdCtx *diag.Context
+	v := (*structVisitor)(l)
+
+	ctx := l.eventCtx(msg, true)
+	l.limit.reset()
+	err := v.Process(ctx)
+	if err == nil && l.limit.truncated {
+		// Truncated values are only detected while encoding. Encode the event
+		// again, so to report log.truncated.
+		l.reset()
+		ctx.AddField(logTruncated)
+		err = v.Process(ctx)
+	}
+	if err == errMaxEventSize {
+		// Drop the context and error details, such that the message is still
+		// reported.
+		l.reset()
+		ctx = l.eventCtx(msg, false)
+		ctx.AddField(logTruncated)
+		l.limit.maxString = l.limits.MaxEventSize / 4
+		if l.limits.MaxStringLen > 0 && l.limits.MaxStringLen < l.limit.maxString {
+			l.limit.maxString = l.limits.MaxStringLen
+		}
+		err = v.Process(ctx)
+	}
+
+	if err != nil {
+		l.reset()
+		return
+	}
+
+	event := l.buf.Bytes()
+	if l.framing != FrameNone || l.bulk != nil {
+		l.frame = l.frame[:0]
+		if l.bulk != nil {
+			l.frame = l.bulk.appendAction(l.frame, msg)
+		}
+		l.frame = l.framing.AppendFrame(l.frame, event)
+		event = l.frame
+	}
+	l.out.Write(event)
+	l.buf.Reset()
+	if cap(l.frame) > persistentFrameBufferSize {
+		l.frame = nil
+	}
+}
+
+// eventCtx builds the context of the event to be reported. The context of
+// the message and the details of errors are only included if full is set.
+func (l *structLayout) eventCtx(msg backend.Message, full bool) *diag.Context {
+	var userCtx, msgCtx *diag.Context
 
-	if msg.Context.Len() > 0 {
-		userCtx = msg.Context.User()
-		stdCtx = msg.Context.Standardized()
+	if full && msg.Context.Len() > 0 {
+		msgCtx = l.filter.context(l.redact.context(msg.Context))
+		userCtx = msgCtx.User()
 	}
 
 	file := msg.Caller.File()
+	if l.relPaths {
+		file = moduleRelativePath(file, msg.Caller.Function())
+	}
 
-	ctx := diag.NewContext(stdCtx, nil)
+	// Standardized fields and fields set by the layout take precedence over
+	// user fields reported at the root of the document.
+	ctx := l.userFields.context(userCtx)
+	addStandardized(ctx, msgCtx)
 	ctx.AddFields([]diag.Field{
 		ecs.Log.Level(msg.Level.String()),
 
 		ecs.Log.Origin.File.Name(file),
 		ecs.Log.Origin.File.Line(msg.Caller.Line()),
 
-		ecs.Message(msg.Message),
+		ecs.Message(l.redact.string(msg.Message)),
 	}...)
 	if msg.Name != "" {
 		ctx.AddField(ecs.Log.Logger(msg.Name))
 	}
-
-	if userCtx.Len() > 0 {
-		ctx.AddField(diag.Any("fields", &userCtx))
+	if l.eventTimestamp {
+		ts := eventTime(msg).UTC().Format(time.RFC3339Nano)
+		ctx.AddField(diag.Field{Key: "@timestamp", Value: diag.ValString(ts), Standardized: true})
+	}
+	if l.bulk != nil {
+		ctx.AddFields(l.bulk.fields(msg)...)
+	}
+	if l.ecsVersion != "" {
+		ctx.AddField(ecs.ECS.Version(l.ecsVersion))
+	}
+	if l.originFunction {
+		if fn := msg.Caller.Function(); fn != "" {
+			ctx.AddField(ecs.Log.Origin.Function(fn))
+		}
 	}
 
 	// Add error values to the context. So to guarantee an error value is not
@@ -134,50 +279,65 @@ func (l *structLayout) Log(msg backend.Message) {
 		break
 	case 1:
 		cause := msg.Causes[0]
-		if errCtx := buildErrCtx(cause); errCtx.Len() > 0 {
-			ctx.AddField(diag.Any("error.ctx", &errCtx))
+		ctx.AddField(diag.String("error.message", l.redact.string(cause.Error())))
+		ctx.AddField(diag.String("error.type", errType(cause, l.rootErrType)))
+		if !full {
+			break
+		}
+
+		if errCtx := buildErrCtx(cause, l.redact, l.filter, l.userFields); errCtx.Len() > 0 {
+			ctx.AddField(diag.Any("error.ctx", errCtx))
+		}
+		// Errors without a trace of their own, e.g. created by fmt.Errorf,
+		// report the trace of the deepest cause in the chain.
+		pcs := errStackPCs(cause)
+		if len(pcs) == 0 {
+			pcs = errChainStackPCs(cause)
+		}
+		if len(pcs) > 0 {
+			ctx.AddField(diag.String("error.stack_trace", formatStackTrace(pcs)))
 		}
-		ctx.AddField(diag.String("error.message", cause.Error()))
 
 		if file, line := sderr.At(cause); file != "" {
 			ctx.AddField(diag.String("error.at.file", file))
 			ctx.AddField(diag.Int("error.at.line", line))
 		}
 
-		n := sderr.NumCauses(cause)
+		path := newErrPath(l.limits.MaxErrorDepth).push(cause)
+		path.trace = pcs
+		n := errNumCauses(cause)
 		switch n {
 		case 0:
 			// nothing
 		case 1:
-			ctx.AddField(diag.Any("error.cause", errorVal{sderr.Unwrap(cause)}))
+			if inner := errUnwrap(cause); path.follow(inner) {
+				ctx.AddField(diag.Any("error.cause", errorVal{inner, path}))
+			} else if path.atLimit() {
+				ctx.AddField(logTruncated)
+			}
 
 		default:
-			ctx.AddField(diag.Any("error.causes", multiErrOf{cause}))
+			ctx.AddField(diag.Any("error.causes", multiErrOf{cause, path}))
 		}
 
 	default:
-		ctx.AddField(diag.Any("error.causes", multiErr{msg.Causes}))
+		if full {
+			ctx.AddField(diag.Any("error.causes", multiErr{msg.Causes}))
+		}
 	}
 
 	// link predefined fields
 	if l.fields.Len() > 0 {
 		ctx = diag.NewContext(l.fields, ctx)
 	}
-
-	v := (*structVisitor)(l)
-	if err := v.Process(ctx); err != nil {
-		l.reset()
-	} else {
-		l.out.Write(l.buf.Bytes())
-		l.buf.Reset()
-	}
+	return ctx
 }
 
 func (v *structVisitor) Process(ctx *diag.Context) error {
 	if err := v.Begin(); err != nil {
 		return err
 	}
-	if err := ctx.VisitStructured(v); err != nil {
+	if err := visitOrdered(ctx, v.order, true, v); err != nil {
 		return err
 	}
 	return v.End()
@@ -200,11 +360,13 @@ func (v structVisitor) OnObjEnd() error {
 func (v structVisitor) OnValue(key string, val diag.Value) error {
 	var err error
 
+	depth := v.limit.depth()
 	if err = v.visitor.OnKey(key); err != nil {
 		return err
 	}
 
 	val.Reporter.Ifc(&val, func(ifc interface{}) {
+		ifc = v.encoders.encode(ifc)
 		switch val := ifc.(type) {
 		case *diag.Context:
 			if err = v.Begin(); err != nil {
@@ -216,23 +378,37 @@ func (v structVisitor) OnValue(key string, val diag.Value) error {
 			err = v.End()
 
 		case errorVal: // error cause
-			err = v.OnErrorValue(val.err, false)
+			err = v.OnErrorValue(val.err, false, val.path)
 
 		case multiErrOf:
-			err = v.OnMultiErrValueIter(val.err)
+			err = v.OnMultiErrValueIter(val.err, val.path)
 
 		case multiErr:
 			err = v.OnMultiErr(val.errs)
 
 		default:
-			err = v.types.Fold(ifc)
+			if v.limit.enterField() {
+				defer v.limit.leaveField()
+			}
+			err = v.foldValue(ifc)
 		}
 	})
 
+	if err == errMaxDepth {
+		err = v.limit.recover(depth)
+	}
 	return err
 }
 
-func (v structVisitor) OnErrorValue(err error, withCtx bool) error {
+func (v structVisitor) OnErrorValue(err error, withCtx bool, parent *errPath) error {
+	path := parent.push(err)
+
+	// Causes sharing the stack trace with one of their parents, e.g. errors
+	// wrapped without capturing a new trace, do not repeat the trace.
+	if pcs := errStackPCs(err); !parent.tracedBefore(pcs) {
+		path.trace = pcs
+	}
+
 	if err := v.Begin(); err != nil {
 		return err
 	}
@@ -261,67 +437,103 @@ func (v structVisitor) OnErrorValue(err error, withCtx bool) error {
 		}
 	}
 
-	if withCtx {
-		ctx := buildErrCtx(err)
-		if ctx.Len() > 0 {
-			if err := v.visitor.OnKey("ctx"); err != nil {
-				return err
-			}
-			if err := v.Begin(); err != nil {
-				return err
-			}
-			if err := ctx.VisitStructured(v); err != nil {
-				return err
-			}
-			if err := v.End(); err != nil {
-				return err
-			}
+	if withCtx && v.order == OrderDefault {
+		if err := v.writeErrCtx(err); err != nil {
+			return err
 		}
 	}
 
-	n := sderr.NumCauses(err)
+	n := errNumCauses(err)
 	switch n {
 	case 0:
 		// nothing to do
 
 	case 1:
 		// add cause
-		cause := sderr.Cause(err, 0)
-		if cause != nil {
-			if err := v.OnValue("cause", diag.ValAny(errorVal{cause})); err != nil {
+		cause := errCause(err, 0)
+		if path.follow(cause) {
+			if err := v.OnValue("cause", diag.ValAny(errorVal{cause, path})); err != nil {
 				return err
 			}
+		} else if path.atLimit() {
+			v.limit.truncated = true
 		}
 
 	default:
-		if err := v.OnValue("causes", diag.ValAny(multiErrOf{err})); err != nil {
+		if err := v.OnValue("causes", diag.ValAny(multiErrOf{err, path})); err != nil {
 			return err
 		}
 
 	}
 
+	// Sorted orders report the context after the causes, to keep the keys of
+	// the error object in lexicographic order.
+	if withCtx && v.order != OrderDefault {
+		if err := v.writeErrCtx(err); err != nil {
+			return err
+		}
+	}
+
 	if err := v.visitor.OnKey("message"); err != nil {
 		return err
 	}
-	if err := v.visitor.OnString(err.Error()); err != nil {
+	if err := v.visitor.OnString(v.redact.string(err.Error())); err != nil {
+		return err
+	}
+
+	if trace := formatStackTrace(path.trace); trace != "" {
+		if err := v.visitor.OnKey("stack_trace"); err != nil {
+			return err
+		}
+		if err := v.visitor.OnString(trace); err != nil {
+			return err
+		}
+	}
+
+	if err := v.visitor.OnKey("type"); err != nil {
+		return err
+	}
+	if err := v.visitor.OnString(errType(err, v.rootErrType)); err != nil {
 		return err
 	}
 
 	return v.End()
 }
 
-func (v structVisitor) OnMultiErrValueIter(parent error) error {
+// writeErrCtx reports the context of err and its linear chain of causes as
+// `ctx`.
+func (v structVisitor) writeErrCtx(err error) error {
+	ctx := buildErrCtx(err, v.redact, v.filter, v.userFields)
+	if ctx.Len() == 0 {
+		return nil
+	}
+
+	if err := v.visitor.OnKey("ctx"); err != nil {
+		return err
+	}
+	if err := v.Begin(); err != nil {
+		return err
+	}
+	if err := ctx.VisitStructured(v); err != nil {
+		return err
+	}
+	return v.End()
+}
+
+func (v structVisitor) OnMultiErrValueIter(parent error, path *errPath) error {
 	if err := v.visitor.OnArrayStart(-1, structform.AnyType); err != nil {
 		return err
 	}
 
-	n := sderr.NumCauses(parent)
+	n := errNumCauses(parent)
 	for i := 0; i < n; i++ {
-		cause := sderr.Cause(parent, i)
-		if cause != nil {
-			if err := v.OnErrorValue(cause, true); err != nil {
+		cause := errCause(parent, i)
+		if path.follow(cause) {
+			if err := v.OnErrorValue(cause, true, path); err != nil {
 				return err
 			}
+		} else if path.atLimit() {
+			v.limit.truncated = true
 		}
 	}
 
@@ -335,7 +547,7 @@ func (v structVisitor) OnMultiErr(errs []error) error {
 
 	for _, err := range errs {
 		if err != nil {
-			if err := v.OnErrorValue(err, true); err != nil {
+			if err := v.OnErrorValue(err, true, newErrPath(v.limits.MaxErrorDepth)); err != nil {
 				return err
 			}
 		}
@@ -344,7 +556,7 @@ func (v structVisitor) OnMultiErr(errs []error) error {
 	return v.visitor.OnArrayFinished()
 }
 
-func buildErrCtx(err error) (errCtx *diag.Context) {
+func buildErrCtx(err error, redact *redactor, filter *fieldFilter, user *userFieldsMapping) (errCtx *diag.Context) {
 	var linkedCtx *diag.Context
 
 	causeCtx := sderr.Context(err)
@@ -354,26 +566,32 @@ func buildErrCtx(err error) (errCtx *diag.Context) {
 		linkedCtx = linkLinearErrCtx(linkedCtx, err)
 	}
 
-	stdCtx := linkedCtx.Standardized()
-	errCtx = diag.NewContext(stdCtx, nil)
-
-	if userCtx := linkedCtx.User(); userCtx.Len() > 0 {
-		errCtx.AddField(diag.Any("fields", &userCtx))
+	if linkedCtx.Len() == 0 {
+		return nil
 	}
 
+	linkedCtx = filter.context(redact.context(linkedCtx))
+	errCtx = user.context(linkedCtx.User())
+	addStandardized(errCtx, linkedCtx)
 	return errCtx
 }
 
 // linkLinearErrCtx links all error context in a linear chain. Stops if a
 // multierror is discovered.
 func linkLinearErrCtx(ctx *diag.Context, err error) *diag.Context {
+	var path *errPath
 	for err != nil {
-		n := sderr.NumCauses(err)
+		n := errNumCauses(err)
 		if n != 1 {
 			return ctx
 		}
 
-		cause := sderr.Unwrap(err)
+		path = path.push(err)
+		cause := errUnwrap(err)
+		if !path.follow(cause) {
+			return ctx
+		}
+
 		causeCtx := sderr.Context(cause)
 		if causeCtx.Len() > 0 {
 			ctx = diag.NewContext(ctx, causeCtx)
@@ -0,0 +1,353 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+)
+
+// SyslogFormat selects the syslog message format.
+type SyslogFormat uint8
+
+const (
+	// RFC5424 messages report the context as STRUCTURED-DATA.
+	RFC5424 SyslogFormat = iota
+
+	// RFC3164 (BSD syslog) messages append the context and the error
+	// messages to the message.
+	RFC3164
+)
+
+// Facility is the syslog facility code.
+type Facility uint8
+
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	_
+	_
+	_
+	_
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// DefaultSyslogSDID is the SD-ID of the STRUCTURED-DATA element used to
+// report the context, if no SD-ID is configured.
+const DefaultSyslogSDID = "ecslog@32473"
+
+// SyslogConfig configures the syslog layout.
+type SyslogConfig struct {
+	Format   SyslogFormat
+	Facility Facility
+
+	// AppName is reported as APP-NAME (RFC5424) or TAG (RFC3164). The logger
+	// name is used if AppName is empty, and the program name if neither is
+	// set. The logger name is always reported as MSGID.
+	AppName string
+
+	// Hostname defaults to the hostname reported by the operating system.
+	Hostname string
+
+	// SDID is the SD-ID of the STRUCTURED-DATA element used to report the
+	// context. Defaults to DefaultSyslogSDID.
+	SDID string
+}
+
+type syslogLayout struct {
+	out      io.Writer
+	buf      bytes.Buffer
+	format   SyslogFormat
+	facility Facility
+	appName  string
+	hostname string
+	procID   string
+	sdID     string
+	redact   *redactor
+	filter   *fieldFilter
+	encoders valueEncoders
+	limits   Limits
+
+	// params maps the SD-PARAM names of the current message to their keys.
+	params map[string]string
+}
+
+// Syslog creates a layout writing syslog messages. Each message is written
+// with a single Write call and without any framing, so the appender can frame
+// the message as required by the transport.
+//
+// Log levels are mapped to syslog severities. The context is encoded as
+// STRUCTURED-DATA in RFC5424 messages, with nested fields being flattened
+// into dotted names. Parameter names are limited to 32 characters by RFC5424.
+// Longer names are shortened, replacing the tail with a hash of the full
+// name, so fields with a long common prefix are reported under distinct
+// names.
+// Error messages are reported as `error.message` parameters in RFC5424
+// messages, and as `error` fields in RFC3164 messages.
+func Syslog(cfg SyslogConfig, opts ...Option) Factory {
+	return func(out io.Writer) (Layout, error) {
+		o, err := applyOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.Format > RFC3164 {
+			return nil, fmt.Errorf("unknown syslog format %v", cfg.Format)
+		}
+		if cfg.Facility > FacilityLocal7 {
+			return nil, fmt.Errorf("invalid syslog facility %v", cfg.Facility)
+		}
+
+		hostname := cfg.Hostname
+		if hostname == "" {
+			if hostname, err = os.Hostname(); err != nil {
+				return nil, err
+			}
+		}
+
+		sdID := cfg.SDID
+		if sdID == "" {
+			sdID = DefaultSyslogSDID
+		}
+		if sdID != syslogName(sdID, 32) {
+			return nil, fmt.Errorf("invalid SD-ID '%v'", sdID)
+		}
+
+		return &syslogLayout{
+			out:      out,
+			format:   cfg.Format,
+			facility: cfg.Facility,
+			appName:  cfg.AppName,
+			hostname: syslogName(hostname, 255),
+			procID:   strconv.Itoa(os.Getpid()),
+			sdID:     sdID,
+			redact:   o.redact,
+			filter:   o.filter,
+			encoders: o.encoders,
+			limits:   o.limits,
+			params:   map[string]string{},
+		}, nil
+	}
+}
+
+func (l *syslogLayout) UseContext() bool { return true }
+
+func (l *syslogLayout) Log(msg backend.Message) {
+	defer l.buf.Reset()
+
+	ts := eventTime(msg)
+	pri := int(l.facility)*8 + int(syslogSeverity(msg.Level))
+
+	appName := l.appName
+	if appName == "" {
+		appName = msg.Name
+	}
+	if appName == "" {
+		appName = filepath.Base(os.Args[0])
+	}
+
+	ctx := l.filter.context(l.redact.context(msg.Context))
+	message := l.redact.string(msg.Message)
+
+	switch l.format {
+	case RFC5424:
+		fmt.Fprintf(&l.buf, "<%d>1 %v %v %v %v %v ",
+			pri,
+			ts.Format("2006-01-02T15:04:05.000000Z07:00"),
+			l.hostname,
+			syslogName(appName, 48),
+			l.procID,
+			syslogNilValue(syslogName(msg.Name, 32)),
+		)
+		if err := l.writeStructuredData(ctx, msg.Causes); err != nil {
+			return
+		}
+		if message != "" {
+			l.buf.WriteByte(' ')
+			l.buf.WriteString(message)
+		}
+
+	case RFC3164:
+		fmt.Fprintf(&l.buf, "<%d>%v %v %v[%v]: ",
+			pri,
+			ts.Format(time.Stamp),
+			l.hostname,
+			syslogName(appName, 32),
+			l.procID,
+		)
+		l.buf.WriteString(strings.Replace(message, "\n", " ", -1))
+
+		p := &textCtxPrinter{buf: &l.buf, prefix: " | ", encoders: l.encoders, limits: l.limits}
+		if err := ctx.VisitKeyValues(p); err != nil {
+			return
+		}
+		for _, cause := range msg.Causes {
+			if cause != nil {
+				p.onKey("error")
+				p.writeQuoted(l.redact.string(cause.Error()))
+			}
+		}
+	}
+
+	l.out.Write(l.buf.Bytes())
+}
+
+func (l *syslogLayout) writeStructuredData(ctx *diag.Context, causes []error) error {
+	start := l.buf.Len()
+	l.buf.WriteByte('[')
+	l.buf.WriteString(l.sdID)
+	n := 0
+	for name := range l.params {
+		delete(l.params, name)
+	}
+
+	err := visitFlatValues(ctx, "", func(key string, value interface{}) error {
+		if value == nil {
+			return nil
+		}
+		str, err := l.encoders.encodeString(value, l.limits)
+		if err != nil {
+			return err
+		}
+		l.writeParam(key, str)
+		n++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, cause := range causes {
+		if cause != nil {
+			l.writeParam("error.message", l.redact.string(cause.Error()))
+			n++
+		}
+	}
+
+	if n == 0 {
+		l.buf.Truncate(start)
+		l.buf.WriteByte('-')
+		return nil
+	}
+
+	l.buf.WriteByte(']')
+	return nil
+}
+
+// writeParam writes an SD-PARAM. The characters '"', '\', and ']' are escaped
+// in the parameter value. Keys mapping to the name of another key already
+// written, e.g. after replacing invalid characters, get a hash suffix.
+func (l *syslogLayout) writeParam(key, value string) {
+	name := syslogParamName(key)
+	if other, exists := l.params[name]; exists && other != key {
+		name = syslogHashedName(name, key)
+	}
+	l.params[name] = key
+
+	l.buf.WriteByte(' ')
+	l.buf.WriteString(name)
+	l.buf.WriteString(`="`)
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '"', '\\', ']':
+			l.buf.WriteByte('\\')
+			l.buf.WriteByte(c)
+		default:
+			l.buf.WriteByte(c)
+		}
+	}
+	l.buf.WriteByte('"')
+}
+
+// syslogSeverity maps log levels to syslog severities.
+func syslogSeverity(lvl backend.Level) uint8 {
+	switch lvl {
+	case backend.Trace, backend.Debug:
+		return 7 // debug
+	case backend.Info:
+		return 6 // informational
+	case backend.Error:
+		return 3 // error
+	default:
+		return 5 // notice
+	}
+}
+
+// syslogName sanitizes header fields and parameter names. Only printable
+// ASCII characters, excluding '=', ' ', ']', and '"' are allowed. Invalid
+// characters are replaced with '_'. The name is truncated to max characters.
+func syslogName(s string, max int) string {
+	name := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(name) > max {
+		name = name[:max]
+	}
+	return name
+}
+
+// syslogParamName creates the SD-PARAM name for key. Names longer than
+// 32 characters are shortened via syslogHashedName.
+func syslogParamName(key string) string {
+	const max = 32
+	name := syslogName(key, len(key))
+	if len(name) > max {
+		name = syslogHashedName(name, key)
+	}
+	return name
+}
+
+// syslogHashedName replaces the tail of name with '~' and the FNV-1a hash of
+// key, such that the result has at most 32 characters.
+func syslogHashedName(name, key string) string {
+	const max, suffixLen = 32, 9
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	if len(name) > max-suffixLen {
+		name = name[:max-suffixLen]
+	}
+	return fmt.Sprintf("%v~%08x", name, h.Sum32())
+}
+
+func syslogNilValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSyslogErrors(t *testing.T) {
+	cases := map[string]struct {
+		format SyslogFormat
+		ctx    []interface{}
+		want   string
+	}{
+		"rfc5424": {
+			format: RFC5424,
+			ctx:    []interface{}{"id", 42},
+			want:   `[ecslog@32473 id="42" error.message="connection \] closed"] failed`,
+		},
+		"rfc3164": {
+			format: RFC3164,
+			ctx:    []interface{}{"id", 42},
+			want:   `: failed | id=42 error="connection ] closed"`,
+		},
+		"rfc3164 without context": {
+			format: RFC3164,
+			want:   `: failed | error="connection ] closed"`,
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("failed", test.ctx...)
+			msg.Causes = []error{errors.New("connection ] closed"), nil}
+
+			cfg := SyslogConfig{Format: test.format, AppName: "app", Hostname: "host"}
+			out := logString(t, Syslog(cfg), msg)
+			if !strings.HasSuffix(out, test.want) {
+				t.Errorf("expected message ending with %q, got %q", test.want, out)
+			}
+		})
+	}
+}
+
+func TestSyslogParamNames(t *testing.T) {
+	prefix := strings.Repeat("a", 32)
+	short := strings.Repeat("b", 32)
+
+	msg := testMessage("test",
+		prefix+".first", 1,
+		prefix+".second", 2,
+		short, 3,
+		"x y", 4,
+		"x_y", 5,
+	)
+	msg.Causes = []error{errors.New("a"), errors.New("b")}
+
+	cfg := SyslogConfig{AppName: "app", Hostname: "host"}
+	factory := Syslog(cfg)
+	out := logString(t, factory, msg)
+
+	paramPattern := regexp.MustCompile(` ([^ ="]+)="([^"]*)"`)
+	matches := paramPattern.FindAllStringSubmatch(out, -1)
+	if len(matches) != 7 {
+		t.Fatalf("expected 7 parameters, got %v in %q", len(matches), out)
+	}
+
+	names := map[string]string{}
+	for _, m := range matches {
+		name, value := m[1], m[2]
+		if len(name) > 32 {
+			t.Errorf("parameter name %q exceeds 32 characters", name)
+		}
+		if prev, exists := names[name]; exists && name != "error.message" {
+			t.Errorf("duplicate parameter name %q for values %q and %q", name, prev, value)
+		}
+		names[name] = value
+	}
+
+	if names[short] != "3" {
+		t.Errorf("expected name of 32 characters to be unchanged, got %v", names)
+	}
+	if names["x_y"] != "4" {
+		t.Errorf("expected first key to keep its name, got %v", names)
+	}
+
+	// names are stable between messages
+	if again := logString(t, factory, msg); paramPattern.FindAllString(again, -1)[0] != matches[0][0] {
+		t.Errorf("parameter names differ between messages: %q, %q", out, again)
+	}
+}
@@ -0,0 +1,105 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"strings"
+
+	"github.com/urso/diag"
+)
+
+// defaultUserNamespace is the namespace user fields are reported in by the
+// structured layout.
+const defaultUserNamespace = "fields"
+
+// userFieldsMapping configures where the structured layout reports fields
+// not defined by ECS.
+type userFieldsMapping struct {
+	namespace string // user fields are reported at the root if empty
+	labels    bool
+}
+
+// UserFieldsNamespace configures the structured layout to report fields that
+// are not standardized by ECS under ns. The fields are reported at the root
+// of the document if ns is empty. Standardized fields and fields set by the
+// layout take precedence over user fields at the root of the document.
+// The default namespace is "fields".
+func UserFieldsNamespace(ns string) Option {
+	return func(o *options) error {
+		o.userNamespace = &ns
+		return nil
+	}
+}
+
+// Labels configures the structured layout to report user fields with string
+// values as ECS `labels.*`. Only fields with keys without dots are reported
+// as labels, as ECS labels must not be nested. All other user fields are
+// reported in the namespace configured via UserFieldsNamespace.
+func Labels() Option {
+	return func(o *options) error {
+		o.labels = true
+		return nil
+	}
+}
+
+func newUserFieldsMapping(o options) *userFieldsMapping {
+	m := &userFieldsMapping{namespace: defaultUserNamespace, labels: o.labels}
+	if o.userNamespace != nil {
+		m.namespace = *o.userNamespace
+	}
+	return m
+}
+
+// context creates a new context with the user fields being mapped into the
+// configured namespace and labels.
+func (m *userFieldsMapping) context(user *diag.Context) *diag.Context {
+	ctx := diag.NewContext(nil, nil)
+	if user.Len() == 0 {
+		return ctx
+	}
+
+	if !m.labels {
+		m.addNamespace(ctx, user)
+		return ctx
+	}
+
+	other := diag.NewContext(nil, nil)
+	user.VisitKeyValues(visitValues(func(key string, v diag.Value) error {
+		if isLabel(key, v) {
+			str, _ := stringValue(v)
+			ctx.AddField(diag.Field{Key: "labels." + key, Value: diag.ValString(str), Standardized: true})
+		} else {
+			other.Add(key, v)
+		}
+		return nil
+	}))
+	m.addNamespace(ctx, other)
+	return ctx
+}
+
+func (m *userFieldsMapping) addNamespace(ctx, user *diag.Context) {
+	if user.Len() == 0 {
+		return
+	}
+
+	if m.namespace != "" {
+		ctx.AddField(diag.Any(m.namespace, user))
+		return
+	}
+
+	user.VisitKeyValues(visitValues(func(key string, v diag.Value) error {
+		ctx.Add(key, v)
+		return nil
+	}))
+}
+
+// isLabel checks if a user field is reported as ECS label, if labels are
+// enabled.
+func isLabel(key string, v diag.Value) bool {
+	_, ok := stringValue(v)
+	return ok && !strings.Contains(key, ".")
+}
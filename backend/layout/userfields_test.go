@@ -0,0 +1,94 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"testing"
+
+	"github.com/urso/diag-ecs/ecs"
+)
+
+func TestUserFields(t *testing.T) {
+	cases := map[string]struct {
+		opts    []Option
+		present map[string]interface{}
+		missing []string
+	}{
+		"default namespace": {
+			present: map[string]interface{}{
+				"fields.id":       float64(42),
+				"fields.app.name": "svc",
+				"fields.message":  "user",
+				"host.hostname":   "h1",
+				"message":         "hello",
+			},
+			missing: []string{"id", "labels"},
+		},
+		"custom namespace": {
+			opts: []Option{UserFieldsNamespace("app_fields")},
+			present: map[string]interface{}{
+				"app_fields.id":       float64(42),
+				"app_fields.app.name": "svc",
+				"host.hostname":       "h1",
+			},
+			missing: []string{"fields", "id"},
+		},
+		"root": {
+			opts: []Option{UserFieldsNamespace("")},
+			present: map[string]interface{}{
+				"id":            float64(42),
+				"app.name":      "svc",
+				"host.hostname": "h1",
+				"message":       "hello",
+			},
+			missing: []string{"fields"},
+		},
+		"labels": {
+			opts: []Option{Labels()},
+			present: map[string]interface{}{
+				"labels.message":  "user",
+				"fields.id":       float64(42),
+				"fields.app.name": "svc",
+				"message":         "hello",
+			},
+			missing: []string{"labels.id", "labels.app", "fields.message"},
+		},
+		"labels at root": {
+			opts: []Option{Labels(), UserFieldsNamespace("")},
+			present: map[string]interface{}{
+				"labels.message": "user",
+				"id":             float64(42),
+				"app.name":       "svc",
+			},
+			missing: []string{"fields"},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			msg := testMessage("hello",
+				"id", 42,
+				"app.name", "svc",
+				"message", "user",
+				ecs.Host.Hostname("h1"),
+			)
+			event := logJSON(t, JSON(nil, test.opts...), msg)
+
+			for key, want := range test.present {
+				if got, ok := lookup(event, key); !ok || got != want {
+					t.Errorf("%v: got %v, want %v", key, got, want)
+				}
+			}
+			for _, key := range test.missing {
+				if got, ok := lookup(event, key); ok {
+					t.Errorf("%v: unexpected value %v", key, got)
+				}
+			}
+		})
+	}
+}
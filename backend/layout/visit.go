@@ -0,0 +1,58 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package layout
+
+import (
+	"errors"
+
+	"github.com/urso/diag"
+)
+
+// visitValues adapts a function to the diag.Visitor interface. It is meant to
+// be used with (*diag.Context).VisitKeyValues, which reports flattened keys
+// only.
+type visitValues func(key string, v diag.Value) error
+
+// errStopVisit is used by visitor callbacks to stop iterating a context early.
+var errStopVisit = errors.New("stop visit")
+
+func (fn visitValues) OnObjStart(_ string) error              { return nil }
+func (fn visitValues) OnObjEnd() error                        { return nil }
+func (fn visitValues) OnValue(key string, v diag.Value) error { return fn(key, v) }
+
+// addStandardized copies the standardized fields in from into to.
+//
+// A projection created via (*diag.Context).Standardized loses the projection
+// when linked into another context if it has no local fields. Copying the
+// fields guarantees that only standardized fields are reported.
+func addStandardized(to, from *diag.Context) {
+	if from.Len() == 0 {
+		return
+	}
+
+	from.Standardized().VisitKeyValues(visitValues(func(key string, v diag.Value) error {
+		to.AddField(diag.Field{Key: key, Value: v, Standardized: true})
+		return nil
+	}))
+}
+
+// visitFlatValues reports all values in ctx with their fully qualified key.
+// Nested contexts are flattened into dotted keys.
+func visitFlatValues(ctx *diag.Context, prefix string, fn func(key string, value interface{}) error) error {
+	return ctx.VisitKeyValues(visitValues(func(key string, v diag.Value) error {
+		var err error
+		key = prefix + key
+		v.Reporter.Ifc(&v, func(value interface{}) {
+			if nested, ok := value.(*diag.Context); ok {
+				err = visitFlatValues(nested, key+".", fn)
+			} else {
+				err = fn(key, value)
+			}
+		})
+		return err
+	}))
+}
@@ -0,0 +1,197 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package validate
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/urso/diag"
+)
+
+// typeClass groups Elasticsearch field types accepting the same Go values.
+type typeClass uint8
+
+const (
+	classAny typeClass = iota
+	classString
+	classInt
+	classFloat
+	classBool
+	classDate
+)
+
+func classOf(typ string) typeClass {
+	switch typ {
+	case "keyword", "text", "wildcard", "constant_keyword", "match_only_text", "ip", "geo_point":
+		return classString
+	case "long", "integer", "short", "byte", "unsigned_long":
+		return classInt
+	case "float", "double", "half_float", "scaled_float":
+		return classFloat
+	case "boolean", "bool":
+		return classBool
+	case "date", "date_nanos":
+		return classDate
+	default:
+		return classAny
+	}
+}
+
+// isCompatible checks if the value can be indexed into a field of type typ.
+// Arrays are compatible if all elements are compatible.
+func isCompatible(typ string, v diag.Value) bool {
+	class := classOf(typ)
+	switch v.Reporter.Type() {
+	case diag.IfcType:
+		return isCompatibleIfc(class, v.Interface())
+	case diag.BoolType:
+		return class == classBool || class == classAny
+	case diag.IntType, diag.Int64Type, diag.Uint64Type, diag.DurationType:
+		return class == classInt || class == classFloat || class == classAny
+	case diag.Float64Type:
+		return class == classFloat || class == classAny
+	case diag.TimestampType:
+		return class == classDate || class == classAny
+	case diag.StringType:
+		return class == classString || class == classAny
+	default:
+		return class == classAny
+	}
+}
+
+func isCompatibleIfc(class typeClass, ifc interface{}) bool {
+	if class == classAny || ifc == nil {
+		return true
+	}
+
+	switch ifc.(type) {
+	case time.Time:
+		return class == classDate
+	case net.IP:
+		return class == classString
+	case []byte:
+		return false
+	}
+
+	rv := reflect.ValueOf(ifc)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return class == classBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return class == classInt || class == classFloat
+	case reflect.Float32, reflect.Float64:
+		return class == classFloat
+	case reflect.String:
+		return class == classString
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if !isCompatibleIfc(class, rv.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return true
+		}
+		return isCompatibleIfc(class, rv.Elem().Interface())
+	default:
+		return false
+	}
+}
+
+// isObject checks if the value is encoded as an object.
+func isObject(v diag.Value) bool {
+	if v.Reporter.Type() != diag.IfcType {
+		return false
+	}
+
+	rv := reflect.ValueOf(v.Interface())
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	return rv.Kind() == reflect.Map || rv.Kind() == reflect.Struct
+}
+
+// coerce converts a value into a value compatible with the field type typ.
+func coerce(typ string, v diag.Value) (diag.Value, bool) {
+	ifc := v.Interface()
+	switch classOf(typ) {
+	case classString:
+		switch val := ifc.(type) {
+		case time.Time:
+			return diag.ValString(val.Format(time.RFC3339Nano)), true
+		case fmt.Stringer:
+			return diag.ValString(val.String()), true
+		}
+		if isScalar(ifc) {
+			return diag.ValString(fmt.Sprint(ifc)), true
+		}
+
+	case classInt:
+		switch val := ifc.(type) {
+		case time.Duration:
+			return diag.ValInt64(int64(val)), true
+		case string:
+			if i, err := strconv.ParseInt(val, 10, 64); err == nil {
+				return diag.ValInt64(i), true
+			}
+		case float64:
+			if val == math.Trunc(val) && math.Abs(val) < math.MaxInt64 {
+				return diag.ValInt64(int64(val)), true
+			}
+		case bool:
+			if val {
+				return diag.ValInt(1), true
+			}
+			return diag.ValInt(0), true
+		}
+
+	case classFloat:
+		switch val := ifc.(type) {
+		case string:
+			if f, err := strconv.ParseFloat(val, 64); err == nil {
+				return diag.ValFloat(f), true
+			}
+		case time.Duration:
+			return diag.ValInt64(int64(val)), true
+		}
+
+	case classBool:
+		if s, ok := ifc.(string); ok {
+			if b, err := strconv.ParseBool(s); err == nil {
+				return diag.ValBool(b), true
+			}
+		}
+
+	case classDate:
+		if s, ok := ifc.(string); ok {
+			if ts, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				return diag.ValTime(ts), true
+			}
+		}
+	}
+
+	return v, false
+}
+
+func isScalar(ifc interface{}) bool {
+	switch reflect.ValueOf(ifc).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,255 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+// Package validate provides a backend checking standardized fields against
+// a schema, like ECS.
+package validate
+
+import (
+	"fmt"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/internal/flatctx"
+	"github.com/urso/ecslog/schema"
+)
+
+// Backend validates the standardized fields in the context of log messages,
+// before passing the messages to the wrapped backend.
+type Backend struct {
+	backend   backend.Backend
+	validator *validator
+}
+
+type validator struct {
+	schema       *schema.Schema
+	onViolation  func(backend.Message, []Violation)
+	reportFields bool
+	fix          Fix
+}
+
+// Violation describes a standardized field not matching the schema.
+type Violation struct {
+	Key    string
+	Reason Reason
+	Type   string // field type defined in the schema, if known
+	Value  diag.Value
+}
+
+// Reason is the type of schema violation.
+type Reason uint8
+
+const (
+	// UnknownField reports a field that is not defined in the schema.
+	UnknownField Reason = iota
+
+	// TypeMismatch reports a value that is not compatible with the field type
+	// defined in the schema.
+	TypeMismatch
+
+	// ObjectConflict reports a key that is defined as an object in the schema,
+	// or a key that is nested in a field that is not an object.
+	ObjectConflict
+)
+
+// Fix configures how invalid fields are handled.
+type Fix uint8
+
+const (
+	// FixNone reports invalid fields as is.
+	FixNone Fix = iota
+
+	// FixDrop removes invalid fields from the context.
+	FixDrop
+
+	// FixCoerce converts values to the field type defined in the schema, if
+	// possible. Values that can not be converted are removed. Unknown fields
+	// and fields conflicting with objects are reported as user fields.
+	FixCoerce
+)
+
+// Option configures the validating backend.
+type Option func(*validator) error
+
+// ValidationFieldKey is the user field reporting the list of violations, if
+// enabled via ReportFields.
+const ValidationFieldKey = "ecslog.validation.errors"
+
+// New creates a backend that checks the standardized fields in each log
+// message against s. The ECS schema can be loaded via schema.ECS().
+//
+// Violations are reported via the `ecslog.validation.errors` user field in
+// binaries built with the `ecslog_debug` build tag. Use ReportFields to
+// report the field in all builds, and OnViolation to handle violations
+// programmatically.
+func New(b backend.Backend, s *schema.Schema, opts ...Option) (*Backend, error) {
+	v := &validator{schema: s, reportFields: debugBuild}
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Backend{backend: b, validator: v}, nil
+}
+
+// OnViolation registers a callback, that is run with all violations found in
+// a log message. The message passed is the original message, before fields
+// have been fixed.
+func OnViolation(fn func(msg backend.Message, violations []Violation)) Option {
+	return func(v *validator) error {
+		v.onViolation = fn
+		return nil
+	}
+}
+
+// ReportFields configures the backend to add the list of violations to the
+// log message as `ecslog.validation.errors` user field.
+func ReportFields() Option {
+	return func(v *validator) error {
+		v.reportFields = true
+		return nil
+	}
+}
+
+// FixFields configures how invalid fields are handled.
+func FixFields(fix Fix) Option {
+	return func(v *validator) error {
+		if fix > FixCoerce {
+			return fmt.Errorf("unknown fix mode %v", fix)
+		}
+		v.fix = fix
+		return nil
+	}
+}
+
+func (b *Backend) For(name string) backend.Backend {
+	return &Backend{backend: b.backend.For(name), validator: b.validator}
+}
+
+func (b *Backend) IsEnabled(lvl backend.Level) bool {
+	return b.backend.IsEnabled(lvl)
+}
+
+func (b *Backend) UseContext() bool {
+	return b.backend.UseContext()
+}
+
+func (b *Backend) Log(msg backend.Message) {
+	if msg.Context != nil && b.backend.UseContext() {
+		msg = b.validator.process(msg)
+	}
+	b.backend.Log(msg)
+}
+
+func (v *validator) process(msg backend.Message) backend.Message {
+	var violations []Violation
+	flatctx.Visit(msg.Context.Standardized(), "", func(key string, val diag.Value) {
+		if violation, invalid := v.check(key, val); invalid {
+			violations = append(violations, violation)
+		}
+	})
+	if len(violations) == 0 {
+		return msg
+	}
+
+	if v.onViolation != nil {
+		v.onViolation(msg, violations)
+	}
+
+	ctx := msg.Context
+	if v.fix != FixNone {
+		ctx = v.fixContext(ctx, violations)
+	}
+	if v.reportFields {
+		errs := make([]string, len(violations))
+		for i, violation := range violations {
+			errs[i] = violation.Error()
+		}
+		ctx = diag.NewContext(ctx, nil)
+		ctx.AddField(diag.Any(ValidationFieldKey, errs))
+	}
+
+	msg.Context = ctx
+	return msg
+}
+
+// check validates a single standardized field.
+func (v *validator) check(key string, val diag.Value) (Violation, bool) {
+	fld, ok := v.schema.Lookup(key)
+	switch {
+	case ok && fld.Name != key: // field in object
+		return Violation{}, false
+	case ok:
+		if isCompatible(fld.Type, val) {
+			return Violation{}, false
+		}
+		return Violation{Key: key, Reason: TypeMismatch, Type: fld.Type, Value: val}, true
+	case fld != nil:
+		return Violation{Key: key, Reason: ObjectConflict, Type: fld.Type, Value: val}, true
+	case v.schema.IsNamespace(key):
+		if isObject(val) {
+			return Violation{}, false
+		}
+		return Violation{Key: key, Reason: ObjectConflict, Type: "object", Value: val}, true
+	default:
+		return Violation{Key: key, Reason: UnknownField, Value: val}, true
+	}
+}
+
+// fixContext creates a copy of ctx with the invalid fields being fixed.
+func (v *validator) fixContext(ctx *diag.Context, violations []Violation) *diag.Context {
+	invalid := make(map[string]Violation, len(violations))
+	for _, violation := range violations {
+		invalid[violation.Key] = violation
+	}
+
+	fixed := diag.NewContext(nil, nil)
+	ctx.User().VisitKeyValues(flatctx.Values(func(key string, val diag.Value) {
+		fixed.Add(key, val)
+	}))
+	flatctx.Visit(ctx.Standardized(), "", func(key string, val diag.Value) {
+		violation, exists := invalid[key]
+		if !exists {
+			fixed.AddField(diag.Field{Key: key, Value: val, Standardized: true})
+			return
+		}
+		if v.fix != FixCoerce {
+			return
+		}
+
+		switch violation.Reason {
+		case TypeMismatch:
+			if val, ok := coerce(violation.Type, val); ok {
+				fixed.AddField(diag.Field{Key: key, Value: val, Standardized: true})
+			}
+		default:
+			fixed.Add(key, val)
+		}
+	})
+	return fixed
+}
+
+func (r Reason) String() string {
+	switch r {
+	case UnknownField:
+		return "unknown field"
+	case TypeMismatch:
+		return "type mismatch"
+	case ObjectConflict:
+		return "object conflict"
+	default:
+		return "unknown"
+	}
+}
+
+func (v Violation) Error() string {
+	if v.Type == "" {
+		return fmt.Sprintf("%v: %v", v.Key, v.Reason)
+	}
+	return fmt.Sprintf("%v: %v, expected %v, got %T", v.Key, v.Reason, v.Type, v.Value.Interface())
+}
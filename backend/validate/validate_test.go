@@ -0,0 +1,304 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package validate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/urso/diag"
+
+	"github.com/urso/ecslog/backend"
+	"github.com/urso/ecslog/backend/internal/flatctx"
+	"github.com/urso/ecslog/schema"
+)
+
+// testSchema is independent of the ECS schema, so the tests do not depend on
+// the field definitions of the ecs package.
+const testSchema = `
+- name: base
+  root: true
+  type: group
+  fields:
+    - name: "@timestamp"
+      type: date
+    - name: message
+      type: text
+    - name: labels
+      type: object
+- name: host
+  type: group
+  fields:
+    - name: hostname
+      type: keyword
+    - name: ip
+      type: ip
+- name: http
+  type: group
+  fields:
+    - name: response.status_code
+      type: long
+- name: event
+  type: group
+  fields:
+    - name: risk_score
+      type: float
+    - name: kind
+      type: keyword
+`
+
+type recordBackend struct {
+	messages []backend.Message
+}
+
+func (b *recordBackend) For(_ string) backend.Backend   { return b }
+func (b *recordBackend) IsEnabled(_ backend.Level) bool { return true }
+func (b *recordBackend) UseContext() bool               { return true }
+func (b *recordBackend) Log(msg backend.Message)        { b.messages = append(b.messages, msg) }
+func (b *recordBackend) last() backend.Message          { return b.messages[len(b.messages)-1] }
+
+func newTestBackend(t *testing.T, opts ...Option) (*Backend, *recordBackend) {
+	t.Helper()
+
+	s, err := schema.Parse("test", []byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := &recordBackend{}
+	b, err := New(rec, s, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b, rec
+}
+
+func testMessage(fields ...diag.Field) backend.Message {
+	ctx := diag.NewContext(nil, nil)
+	ctx.AddField(diag.String("user.field", "x"))
+	for _, fld := range fields {
+		fld.Standardized = true
+		ctx.AddField(fld)
+	}
+	return backend.Message{Level: backend.Info, Message: "test", Context: ctx}
+}
+
+// contextFields returns the standardized fields and user fields, keyed by
+// their fully qualified name.
+func contextFields(ctx *diag.Context) (std, user map[string]interface{}) {
+	std, user = map[string]interface{}{}, map[string]interface{}{}
+	flatctx.Visit(ctx.Standardized(), "", func(key string, v diag.Value) { std[key] = v.Interface() })
+	flatctx.Visit(ctx.User(), "", func(key string, v diag.Value) { user[key] = v.Interface() })
+	return std, user
+}
+
+func TestViolations(t *testing.T) {
+	cases := map[string]struct {
+		field  diag.Field
+		reason Reason
+		typ    string
+		valid  bool
+	}{
+		"valid keyword":      {field: diag.String("host.hostname", "h1"), valid: true},
+		"valid text":         {field: diag.String("message", "hello"), valid: true},
+		"valid ip":           {field: diag.String("host.ip", "127.0.0.1"), valid: true},
+		"valid date":         {field: diag.Timestamp("@timestamp", time.Now()), valid: true},
+		"int as float":       {field: diag.Int("event.risk_score", 1), valid: true},
+		"label":              {field: diag.String("labels.env", "prod"), valid: true},
+		"object":             {field: diag.Any("host", map[string]string{"hostname": "h1"}), valid: true},
+		"unknown":            {field: diag.String("host.unknown", "x"), reason: UnknownField},
+		"string as long":     {field: diag.String("http.response.status_code", "200"), reason: TypeMismatch, typ: "long"},
+		"float as long":      {field: diag.Float("http.response.status_code", 1.5), reason: TypeMismatch, typ: "long"},
+		"int as keyword":     {field: diag.Int("event.kind", 1), reason: TypeMismatch, typ: "keyword"},
+		"value for object":   {field: diag.String("host", "h1"), reason: ObjectConflict, typ: "object"},
+		"nested in keyword":  {field: diag.String("event.kind.sub", "x"), reason: ObjectConflict, typ: "keyword"},
+		"string as datetime": {field: diag.String("@timestamp", "now"), reason: TypeMismatch, typ: "date"},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var violations []Violation
+			b, _ := newTestBackend(t, OnViolation(func(_ backend.Message, v []Violation) {
+				violations = v
+			}))
+			b.Log(testMessage(test.field))
+
+			if test.valid {
+				if len(violations) != 0 {
+					t.Fatalf("unexpected violations: %v", violations)
+				}
+				return
+			}
+
+			if len(violations) != 1 {
+				t.Fatalf("expected one violation, got %v", violations)
+			}
+			v := violations[0]
+			if v.Key != test.field.Key || v.Reason != test.reason || v.Type != test.typ {
+				t.Errorf("unexpected violation: %#v", v)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	invalid := diag.String("host.unknown", "x")
+
+	cases := map[string]struct {
+		opts   []Option
+		report bool
+	}{
+		"default":        {report: debugBuild},
+		"with callback":  {opts: []Option{OnViolation(func(backend.Message, []Violation) {})}, report: debugBuild},
+		"with fix":       {opts: []Option{FixFields(FixDrop)}, report: debugBuild},
+		"report fields":  {opts: []Option{ReportFields()}, report: true},
+		"report and fix": {opts: []Option{ReportFields(), FixFields(FixCoerce)}, report: true},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			b, rec := newTestBackend(t, test.opts...)
+			b.Log(testMessage(invalid))
+
+			_, user := contextFields(rec.last().Context)
+			errs, reported := user[ValidationFieldKey]
+			if reported != test.report {
+				t.Fatalf("expected reported=%v, got %v", test.report, errs)
+			}
+			if reported {
+				want := []string{"host.unknown: unknown field"}
+				if got, ok := errs.([]string); !ok || len(got) != 1 || got[0] != want[0] {
+					t.Errorf("got %v, want %v", errs, want)
+				}
+			}
+		})
+	}
+
+	t.Run("invalid fix", func(t *testing.T) {
+		if _, err := New(&recordBackend{}, schema.ECS(), FixFields(FixCoerce+1)); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestFixFields(t *testing.T) {
+	fields := []diag.Field{
+		diag.String("host.hostname", "h1"),
+		diag.String("http.response.status_code", "200"),
+		diag.String("event.risk_score", "high"),
+		diag.String("host.unknown", "x"),
+		diag.String("event.kind.sub", "y"),
+	}
+
+	cases := map[string]struct {
+		fix  Fix
+		std  map[string]interface{}
+		user map[string]interface{}
+	}{
+		"none": {
+			fix: FixNone,
+			std: map[string]interface{}{
+				"host.hostname":             "h1",
+				"http.response.status_code": "200",
+				"event.risk_score":          "high",
+				"host.unknown":              "x",
+				"event.kind.sub":            "y",
+			},
+			user: map[string]interface{}{"user.field": "x"},
+		},
+		"drop": {
+			fix:  FixDrop,
+			std:  map[string]interface{}{"host.hostname": "h1"},
+			user: map[string]interface{}{"user.field": "x"},
+		},
+		"coerce": {
+			fix: FixCoerce,
+			std: map[string]interface{}{
+				"host.hostname":             "h1",
+				"http.response.status_code": int64(200),
+			},
+			user: map[string]interface{}{
+				"user.field":     "x",
+				"host.unknown":   "x",
+				"event.kind.sub": "y",
+			},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			b, rec := newTestBackend(t, FixFields(test.fix))
+			b.Log(testMessage(fields...))
+
+			std, user := contextFields(rec.last().Context)
+			delete(user, ValidationFieldKey)
+			assertFields(t, "standardized", std, test.std)
+			assertFields(t, "user", user, test.user)
+		})
+	}
+}
+
+func assertFields(t *testing.T, kind string, got, want map[string]interface{}) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("expected %v fields %v, got %v", kind, want, got)
+		return
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%v field %v: got %v (%T), want %v (%T)", kind, key, got[key], got[key], value, value)
+		}
+	}
+}
+
+func TestCoerce(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cases := map[string]struct {
+		typ  string
+		in   diag.Value
+		want interface{}
+		ok   bool
+	}{
+		"int to keyword":      {"keyword", diag.ValInt(42), "42", true},
+		"bool to keyword":     {"keyword", diag.ValBool(true), "true", true},
+		"time to keyword":     {"keyword", diag.ValAny(ts), "2020-01-02T03:04:05Z", true},
+		"duration to keyword": {"keyword", diag.ValAny(time.Second), "1s", true},
+		"map to keyword":      {"keyword", diag.ValAny(map[string]int{}), nil, false},
+		"string to long":      {"long", diag.ValString("200"), int64(200), true},
+		"invalid to long":     {"long", diag.ValString("x"), nil, false},
+		"whole float to long": {"long", diag.ValFloat(3), int64(3), true},
+		"float to long":       {"long", diag.ValFloat(1.5), nil, false},
+		"bool to long":        {"long", diag.ValBool(true), 1, true},
+		"duration to long":    {"long", diag.ValAny(time.Second), int64(time.Second), true},
+		"string to float":     {"float", diag.ValString("1.5"), 1.5, true},
+		"string to boolean":   {"boolean", diag.ValString("true"), true, true},
+		"invalid to boolean":  {"boolean", diag.ValString("yes"), nil, false},
+		"string to date":      {"date", diag.ValString("2020-01-02T03:04:05Z"), ts, true},
+		"invalid to date":     {"date", diag.ValString("today"), nil, false},
+		"string to object":    {"object", diag.ValString("x"), nil, false},
+	}
+
+	for name, test := range cases {
+		got, ok := coerce(test.typ, test.in)
+		if ok != test.ok {
+			t.Errorf("%v: expected ok=%v, got %v", name, test.ok, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if v := got.Interface(); v != test.want {
+			if ts, isTime := v.(time.Time); !isTime || !ts.Equal(test.want.(time.Time)) {
+				t.Errorf("%v: got %v (%T), want %v (%T)", name, v, v, test.want, test.want)
+			}
+		}
+	}
+}
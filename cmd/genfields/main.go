@@ -0,0 +1,333 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+// genfields generates type-safe field constructors from ECS compatible
+// schema definitions.
+//
+// Usage:
+//
+//	genfields -out schema.go -fmt -pkg myfields -version 1.0 -schema <path to schema>
+//
+// The schema is read from ECS compatible yaml files (see package schema).
+// Custom definitions are checked against the ECS schema supported by ecslog.
+// Fields that redefine ECS fields, change the type of ECS fields, or conflict
+// with ECS objects are reported as errors. Use -ecs=false to generate code
+// for the ECS schema itself.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/urso/ecslog/schema"
+)
+
+type model struct {
+	Version    string
+	Base       []*value
+	Top        []*namespace
+	Namespaces []*namespace // all namespaces, sorted by fully qualified name
+}
+
+type namespace struct {
+	Name        string
+	FlatName    string
+	Description string
+
+	Children []*namespace
+	Values   []*value
+}
+
+type value struct {
+	Name        string
+	FlatName    string
+	Description string
+	Type        typeInfo
+}
+
+type typeInfo struct {
+	Name        string
+	Constructor string
+}
+
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(([]string)(*f), ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+var (
+	boolType  = typeInfo{Name: "bool", Constructor: "Bool"}
+	strType   = typeInfo{Name: "string", Constructor: "String"}
+	intType   = typeInfo{Name: "int", Constructor: "Int"}
+	longType  = typeInfo{Name: "int64", Constructor: "Int64"}
+	floatType = typeInfo{Name: "float64", Constructor: "Float64"}
+	dateType  = typeInfo{Name: "time.Time", Constructor: "Time"}
+	durType   = typeInfo{Name: "time.Duration", Constructor: "Dur"}
+)
+
+func main() {
+	var (
+		pkgName  string
+		outFile  string
+		version  string
+		fmtCode  bool
+		checkECS bool
+		paths    []string
+		exclude  []string
+	)
+
+	log.SetFlags(0)
+	flag.StringVar(&pkgName, "pkg", "ecs", "Target package name")
+	flag.StringVar(&outFile, "out", "", "Output file. Code is printed to stdout if not set")
+	flag.StringVar(&version, "version", "", "Schema version (required)")
+	flag.BoolVar(&fmtCode, "fmt", false, "Format output")
+	flag.BoolVar(&checkECS, "ecs", true, "Check for collisions with the ECS schema")
+	flag.Var((*stringsFlag)(&paths), "schema", "Schema file or directory. Can be given multiple times")
+	flag.Var((*stringsFlag)(&exclude), "e", "exclude fields")
+	flag.Parse()
+	paths = append(paths, flag.Args()...)
+
+	if len(paths) == 0 {
+		log.Fatal("No schema files given")
+	}
+	if version == "" {
+		log.Fatal("Error: -version required")
+	}
+
+	s, err := schema.Load(version, paths...)
+	if err != nil {
+		log.Fatalf("Error loading schema: %v", err)
+	}
+
+	ignore := map[string]bool{}
+	for _, name := range exclude {
+		ignore[name] = true
+	}
+
+	var errs []string
+	if checkECS {
+		errs = append(errs, ecsCollisions(schema.ECS(), s, ignore)...)
+	}
+
+	m, modelErrs := buildModel(s, ignore)
+	errs = append(errs, modelErrs...)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Println(err)
+		}
+		log.Fatalf("Found %v errors in schema", len(errs))
+	}
+
+	contents, err := execTemplate(codeTmpl, pkgName, m)
+	if err != nil {
+		log.Fatalf("Error creating code: %v", err)
+	}
+
+	if fmtCode {
+		contents, err = format.Source(contents)
+		if err != nil {
+			log.Fatalf("failed to format code: %v", err)
+		}
+	}
+
+	if outFile == "" {
+		os.Stdout.Write(contents)
+		return
+	}
+	if err := ioutil.WriteFile(outFile, contents, 0644); err != nil {
+		log.Fatalf("failed to write file '%v': %v", outFile, err)
+	}
+}
+
+// ecsCollisions checks the custom schema for fields that would break
+// indexing of ECS documents. Custom fields must not redefine ECS fields, and
+// must not conflict with ECS objects.
+func ecsCollisions(ecs, custom *schema.Schema, ignore map[string]bool) []string {
+	var errs []string
+	for _, fld := range custom.Fields() {
+		if ignore[fld.Name] {
+			continue
+		}
+
+		ecsFld, ok := ecs.Lookup(fld.Name)
+		switch {
+		case ok && ecsFld.Name == fld.Name && ecsFld.Type != fld.Type:
+			errs = append(errs, fmt.Sprintf("type collision: field '%v' has type '%v', but ECS defines type '%v'",
+				fld.Name, fld.Type, ecsFld.Type))
+		case ok && ecsFld.Name == fld.Name:
+			errs = append(errs, fmt.Sprintf("name collision: field '%v' is already defined by ECS", fld.Name))
+		case !ok && ecsFld != nil:
+			errs = append(errs, fmt.Sprintf("name collision: field '%v' is nested in ECS field '%v' of type '%v'",
+				fld.Name, ecsFld.Name, ecsFld.Type))
+		case ecs.IsNamespace(fld.Name):
+			errs = append(errs, fmt.Sprintf("name collision: field '%v' is an object in ECS", fld.Name))
+		}
+	}
+	return errs
+}
+
+// buildModel creates the namespaces and values to generate code for. Fields
+// of type object are not generated, as they would accept any value.
+func buildModel(s *schema.Schema, ignore map[string]bool) (*model, []string) {
+	var errs []string
+
+	m := &model{Version: s.Version}
+	namespaces := map[string]*namespace{}
+
+	var getNamespace func(path string) *namespace
+	getNamespace = func(path string) *namespace {
+		if ns := namespaces[path]; ns != nil {
+			return ns
+		}
+
+		name, parent := splitPath(path)
+		ns := &namespace{Name: name, FlatName: path, Description: s.GroupDescription(path)}
+		namespaces[path] = ns
+		if parent == "" {
+			m.Top = append(m.Top, ns)
+		} else {
+			p := getNamespace(parent)
+			p.Children = append(p.Children, ns)
+		}
+		return ns
+	}
+
+	for _, fld := range s.Fields() {
+		if ignore[fld.Name] || fld.Type == "object" {
+			continue
+		}
+
+		if s.IsNamespace(fld.Name) {
+			errs = append(errs, fmt.Sprintf("name collision: field '%v' is also used as object", fld.Name))
+			continue
+		}
+
+		ti, err := getType(fld.Type)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("field '%v': %v", fld.Name, err))
+			continue
+		}
+
+		name, path := splitPath(fld.Name)
+		val := &value{Name: name, FlatName: fld.Name, Description: fld.Description, Type: ti}
+		if path == "" {
+			m.Base = append(m.Base, val)
+		} else {
+			ns := getNamespace(path)
+			ns.Values = append(ns.Values, val)
+		}
+	}
+
+	for _, ns := range namespaces {
+		m.Namespaces = append(m.Namespaces, ns)
+	}
+	sort.Slice(m.Namespaces, func(i, j int) bool {
+		return m.Namespaces[i].FlatName < m.Namespaces[j].FlatName
+	})
+
+	errs = append(errs, goNameCollisions(m)...)
+	return m, errs
+}
+
+// goNameCollisions checks that the generated identifiers are unique. Field
+// names like `user_id` and `user.id` can result in the same Go identifier.
+func goNameCollisions(m *model) []string {
+	var errs []string
+	check := func(scope string, names map[string]string, goName, field string) {
+		if other, exists := names[goName]; exists {
+			errs = append(errs, fmt.Sprintf("name collision: '%v' and '%v' generate the same identifier %v in %v",
+				other, field, goName, scope))
+			return
+		}
+		names[goName] = field
+	}
+
+	names := map[string]string{"Version": "schema version"}
+	for _, ns := range m.Top {
+		check("package scope", names, goTypeName(ns.Name), ns.FlatName)
+	}
+	for _, val := range m.Base {
+		check("package scope", names, goTypeName(val.Name), val.FlatName)
+	}
+
+	types := map[string]string{}
+	for _, ns := range m.Namespaces {
+		check("package scope", types, "ns"+goTypeName(ns.FlatName), ns.FlatName)
+	}
+
+	for _, ns := range m.Namespaces {
+		names := map[string]string{}
+		scope := "namespace " + ns.FlatName
+		for _, child := range ns.Children {
+			check(scope, names, goTypeName(child.Name), child.FlatName)
+		}
+		for _, val := range ns.Values {
+			check(scope, names, goTypeName(val.Name), val.FlatName)
+		}
+	}
+	return errs
+}
+
+func getType(typ string) (typeInfo, error) {
+	switch typ {
+	case "keyword", "text", "wildcard", "constant_keyword", "ip", "geo_point":
+		return strType, nil
+	case "bool", "boolean":
+		return boolType, nil
+	case "integer", "short", "byte":
+		return intType, nil
+	case "long":
+		return longType, nil
+	case "float", "double", "half_float", "scaled_float":
+		return floatType, nil
+	case "date":
+		return dateType, nil
+	case "duration":
+		return durType, nil
+	default:
+		return typeInfo{}, fmt.Errorf("unknown type '%v'", typ)
+	}
+}
+
+func splitPath(in string) (name, parent string) {
+	idx := strings.LastIndexByte(in, '.')
+	if idx < 0 {
+		return in, ""
+	}
+	return in[idx+1:], in[:idx]
+}
+
+func execTemplate(tmpl, pkgName string, m *model) ([]byte, error) {
+	funcs := template.FuncMap{
+		"goName":    goTypeName,
+		"goComment": goCommentify,
+	}
+
+	var buf bytes.Buffer
+	t := template.Must(template.New("").Funcs(funcs).Parse(tmpl))
+	err := t.Execute(&buf, map[string]interface{}{
+		"packageName": pkgName,
+		"model":       m,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("executing code template failed: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,97 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/urso/ecslog/schema"
+)
+
+func TestSchemaErrors(t *testing.T) {
+	ecs, err := schema.Load("ecs", filepath.Join("testdata", "ecs.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		file   string
+		ignore []string
+		errs   []string
+	}{
+		{file: "valid.yml"},
+		{
+			file: "type.yml",
+			errs: []string{"type collision: field 'host.hostname' has type 'long', but ECS defines type 'keyword'"},
+		},
+		{
+			file: "name.yml",
+			errs: []string{"name collision: field 'host.hostname' is already defined by ECS"},
+		},
+		{
+			file:   "name.yml",
+			ignore: []string{"host.hostname"},
+		},
+		{
+			file: "nested.yml",
+			errs: []string{"name collision: field 'host.hostname.short' is nested in ECS field 'host.hostname' of type 'keyword'"},
+		},
+		{
+			file: "object.yml",
+			errs: []string{"name collision: field 'host' is an object in ECS"},
+		},
+		{
+			file: "self_object.yml",
+			errs: []string{"name collision: field 'myapp.request' is also used as object"},
+		},
+		{
+			file: "go_ident.yml",
+			errs: []string{"generate the same identifier RequestID in namespace myapp"},
+		},
+		{
+			file: "go_namespace.yml",
+			errs: []string{
+				"generate the same identifier MyApp in package scope",
+				"generate the same identifier nsMyApp in package scope",
+			},
+		},
+		{
+			file: "unknown_type.yml",
+			errs: []string{"field 'myapp.payload': unknown type 'binary'"},
+		},
+	}
+
+	for _, test := range cases {
+		test := test
+		t.Run(test.file, func(t *testing.T) {
+			s, err := schema.Load("test", filepath.Join("testdata", test.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ignore := map[string]bool{}
+			for _, name := range test.ignore {
+				ignore[name] = true
+			}
+
+			errs := ecsCollisions(ecs, s, ignore)
+			_, modelErrs := buildModel(s, ignore)
+			errs = append(errs, modelErrs...)
+
+			if len(errs) != len(test.errs) {
+				t.Fatalf("expected errors %q, got %q", test.errs, errs)
+			}
+			for i, want := range test.errs {
+				if !strings.Contains(errs[i], want) {
+					t.Errorf("expected error containing %q, got %q", want, errs[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"strings"
+)
+
+var codeTmpl = `// Code generated by genfields. DO NOT EDIT.
+
+package {{ .packageName }}
+
+import (
+	"time"
+
+	"github.com/urso/diag"
+)
+
+type (
+{{- range $ns := .model.Namespaces }}
+	ns{{ $ns.FlatName | goName }} struct {
+	{{- range $sub := $ns.Children }}
+		{{ if $sub.Description }}{{ $sub.Description | goComment }}
+		{{ end -}}
+		{{ $sub.Name | goName }} ns{{ $sub.FlatName | goName }}
+	{{- end }}
+	}
+{{ end -}}
+)
+
+var (
+{{- range $ns := .model.Top }}
+	// {{ $ns.Name | goName }} provides fields in the {{ $ns.FlatName }} namespace.
+	{{ if $ns.Description }}{{ $ns.Description | goComment }}
+	{{ end -}}
+	{{ $ns.Name | goName }} = ns{{ $ns.FlatName | goName }}{}
+{{ end -}}
+)
+
+// Version is the schema version the fields have been generated from.
+const Version = "{{ .model.Version }}"
+
+func stdField(key string, val diag.Value) diag.Field {
+	return diag.Field{Key: key, Value: val, Standardized: true}
+}
+
+func stdTime(key string, val time.Time) diag.Field    { return stdField(key, diag.ValTime(val)) }
+func stdDur(key string, val time.Duration) diag.Field { return stdField(key, diag.ValDuration(val)) }
+func stdString(key, val string) diag.Field            { return stdField(key, diag.ValString(val)) }
+func stdBool(key string, val bool) diag.Field         { return stdField(key, diag.ValBool(val)) }
+func stdInt(key string, val int) diag.Field           { return stdField(key, diag.ValInt(val)) }
+func stdInt64(key string, val int64) diag.Field       { return stdField(key, diag.ValInt64(val)) }
+func stdFloat64(key string, val float64) diag.Field   { return stdField(key, diag.ValFloat(val)) }
+{{ range $value := .model.Base }}
+// {{ $value.Name | goName }} creates the '{{ $value.FlatName }}' field.
+{{ if $value.Description }}{{ $value.Description | goComment }}
+{{ end -}}
+func {{ $value.Name | goName }}(value {{ $value.Type.Name }}) diag.Field {
+	return std{{ $value.Type.Constructor }}("{{ $value.FlatName }}", value)
+}
+{{ end }}
+{{- range $ns := .model.Namespaces }}
+{{- if $ns.Values }}
+// ## {{ $ns.FlatName }} fields
+{{ range $value := $ns.Values }}
+// {{ $value.Name | goName }} creates the '{{ $value.FlatName }}' field.
+{{ if $value.Description }}{{ $value.Description | goComment }}
+{{ end -}}
+func (ns{{ $ns.FlatName | goName }}) {{ $value.Name | goName }}(value {{ $value.Type.Name }}) diag.Field {
+	return std{{ $value.Type.Constructor }}("{{ $value.FlatName }}", value)
+}
+{{ end }}
+{{- end }}
+{{- end }}
+`
+
+// goCommentify converts a description into a Go comment, wrapping lines
+// after 72 characters.
+func goCommentify(s string) string {
+	const width = 72
+
+	var lines []string
+	var line strings.Builder
+	for _, word := range strings.Fields(s) {
+		if line.Len() > 0 && line.Len()+1+len(word) > width {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+		if line.Len() > 0 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(word)
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+
+	for i := range lines {
+		lines[i] = "// " + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func goTypeName(name string) string {
+	var b strings.Builder
+	for _, w := range strings.FieldsFunc(name, isSeparator) {
+		b.WriteString(strings.Title(abbreviations(w)))
+	}
+	return b.String()
+}
+
+// abbreviations capitalizes common abbreviations.
+func abbreviations(abv string) string {
+	switch strings.ToLower(abv) {
+	case "id", "ppid", "pid", "mac", "ip", "iana", "uid", "ecs", "url", "os",
+		"http", "dns", "ssl", "tls", "ttl", "uuid":
+		return strings.ToUpper(abv)
+	default:
+		return abv
+	}
+}
+
+// isSeparator returns true if the character is a field name separator. This
+// is used to detect the separators in fields like ephemeral_id or
+// instance.name.
+func isSeparator(c rune) bool {
+	switch c {
+	case '.', '_', '@', '-':
+		return true
+	default:
+		return false
+	}
+}
@@ -19,7 +19,9 @@ import (
 	"github.com/urso/ecslog"
 	"github.com/urso/ecslog/backend"
 	"github.com/urso/ecslog/backend/appender"
+	"github.com/urso/ecslog/backend/appender/gelf"
 	"github.com/urso/ecslog/backend/appender/rolling"
+	"github.com/urso/ecslog/backend/appender/syslog"
 	"github.com/urso/ecslog/backend/layout"
 	"github.com/urso/sderr"
 )
@@ -36,6 +38,17 @@ func main() {
 		"verbose": func() {
 			testWith(appender.Console(ecslog.Trace, layout.Text(true)))
 		},
+		"console": func() {
+			testWith(appender.Console(ecslog.Trace, layout.Console(true)))
+		},
+		"pretty": func() {
+			testWith(appender.Console(ecslog.Trace, layout.Pretty(layout.Colors(layout.DefaultColorScheme))))
+		},
+		"pattern": func() {
+			testWith(appender.Console(ecslog.Trace, layout.Pattern(
+				"%d{2006-01-02 15:04:05.000} %-5level [%logger{20}] %file:%line %func - %msg %ctx%n",
+			)))
+		},
 		"json": func() {
 			testWith(appender.Console(
 				ecslog.Trace,
@@ -44,6 +57,39 @@ func main() {
 				}),
 			))
 		},
+		"ecs": func() {
+			testWith(appender.Console(ecslog.Trace, layout.ECS(nil, layout.ModuleRelativePaths())))
+		},
+		"logfmt": func() {
+			testWith(appender.Console(
+				ecslog.Trace,
+				layout.Logfmt([]diag.Field{
+					layout.DynTimestamp(time.RFC3339Nano),
+				}),
+			))
+		},
+		"gelf": func() {
+			testWith(appender.Console(ecslog.Trace, layout.GELF("", nil)))
+		},
+		"gelf_udp": func() {
+			testWith(gelf.NewAppender(
+				ecslog.Trace,
+				"udp", "localhost:12201",
+				layout.GELF("", nil),
+				gelf.CompressGZip,
+				0,
+			))
+		},
+		"syslog": func() {
+			testWith(appender.Console(ecslog.Trace, layout.Syslog(layout.SyslogConfig{
+				Facility: layout.FacilityLocal0,
+			})))
+		},
+		"syslog_local": func() {
+			testWith(syslog.NewAppender(ecslog.Trace, "", "", layout.Syslog(layout.SyslogConfig{
+				Facility: layout.FacilityLocal0,
+			})))
+		},
 		"json_file": func() {
 			testWith(rolling.NewAppender(
 				ecslog.Trace,
@@ -174,6 +220,12 @@ func testWith(backend backend.Backend, err error) {
 		sderr.Wrap(io.EOF, "unexpected eof in %{file}", "tx.log"),
 		sderr.Wrap(io.ErrClosedPipe, "remote connection to %{server} closed", "localhost"),
 	)
+
+	log.Errorf("three errors: %v, %v, %v",
+		io.EOF,
+		sderr.Wrap(io.ErrClosedPipe, "remote connection to %{server} closed", "localhost"),
+		sderr.WrapAll([]error{io.ErrShortWrite, io.ErrNoProgress}, "flush failed"),
+	)
 }
 
 func printTitle(title string) {
@@ -8,5 +8,8 @@ require (
 	github.com/urso/diag v0.0.0-20200210123136-21b3cc8eb797
 	github.com/urso/diag-ecs v0.0.0-20200210114345-ab085841dcb9
 	github.com/urso/sderr v0.0.0-20200210124243-c2a16f3d43ec
+	golang.org/x/sys v0.7.0 // indirect
+	golang.org/x/term v0.0.0-20210422114643-f5beecf764ed
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/urso/diag"
 	"github.com/urso/diag/ctxfmt"
@@ -17,13 +18,33 @@ import (
 )
 
 type Logger struct {
-	ctx     *diag.Context
-	name    string
-	backend backend.Backend
+	ctx         *diag.Context
+	name        string
+	backend     backend.Backend
+	formatCheck FormatCheck
 }
 
 type Level = backend.Level
 
+// FormatCheck configures how the logger reacts to format strings that
+// consume fewer arguments than given. Leftover fields and errors are always
+// added to the context and causes of the log message, other values are added
+// as `extra.<index>` user fields.
+type FormatCheck uint8
+
+const (
+	// FormatCheckOff accepts leftover arguments silently.
+	FormatCheckOff FormatCheck = iota
+
+	// FormatCheckField reports the number of leftover arguments that are
+	// neither fields nor errors in the `ecslog.format.extra_args` user field.
+	FormatCheckField
+
+	// FormatCheckPanic panics if leftover arguments that are neither fields
+	// nor errors are found. Use for tests only.
+	FormatCheckPanic
+)
+
 const (
 	Trace Level = backend.Trace
 	Debug Level = backend.Debug
@@ -45,16 +66,30 @@ func (l *Logger) IsEnabled(lvl Level) bool {
 
 func (l *Logger) Named(name string) *Logger {
 	return &Logger{
-		ctx:     diag.NewContext(l.ctx, nil),
-		backend: l.backend.For(name),
-		name:    name,
+		ctx:         diag.NewContext(l.ctx, nil),
+		backend:     l.backend.For(name),
+		name:        name,
+		formatCheck: l.formatCheck,
+	}
+}
+
+// WithFormatCheck creates a new logger, that checks format strings for
+// unused arguments.
+func (l *Logger) WithFormatCheck(check FormatCheck) *Logger {
+	return &Logger{
+		ctx:         diag.NewContext(l.ctx, nil),
+		backend:     l.backend,
+		name:        l.name,
+		formatCheck: check,
 	}
 }
 
 func (l *Logger) With(args ...interface{}) *Logger {
 	nl := &Logger{
-		ctx:     diag.NewContext(l.ctx, nil),
-		backend: l.backend,
+		ctx:         diag.NewContext(l.ctx, nil),
+		name:        l.name,
+		backend:     l.backend,
+		formatCheck: l.formatCheck,
 	}
 	nl.ctx.AddAll(args...)
 	return nl
@@ -62,8 +97,10 @@ func (l *Logger) With(args ...interface{}) *Logger {
 
 func (l *Logger) WithFields(fields ...diag.Field) *Logger {
 	nl := &Logger{
-		ctx:     diag.NewContext(l.ctx, nil),
-		backend: l.backend,
+		ctx:         diag.NewContext(l.ctx, nil),
+		name:        l.name,
+		backend:     l.backend,
+		formatCheck: l.formatCheck,
 	}
 	nl.ctx.AddFields(fields...)
 	return nl
@@ -81,8 +118,10 @@ func (l *Logger) WithDiagnosticContext(ctx *diag.Context) *Logger {
 		merged = diag.NewContext(l.ctx, ctx)
 	}
 	return &Logger{
-		ctx:     diag.NewContext(merged, nil),
-		backend: l.backend,
+		ctx:         diag.NewContext(merged, nil),
+		name:        l.name,
+		backend:     l.backend,
+		formatCheck: l.formatCheck,
 	}
 }
 
@@ -145,12 +184,13 @@ func (l *Logger) logArgsCtx(lvl Level, skip int, args []interface{}) {
 	}
 
 	l.backend.Log(backend.Message{
-		Name:    l.name,
-		Level:   lvl,
-		Caller:  getCaller(skip + 1),
-		Message: msg,
-		Context: ctx,
-		Causes:  causes,
+		Name:      l.name,
+		Level:     lvl,
+		Timestamp: time.Now(),
+		Caller:    getCaller(skip + 1),
+		Message:   msg,
+		Context:   ctx,
+		Causes:    causes,
 	})
 }
 
@@ -164,12 +204,13 @@ func (l *Logger) logArgs(lvl Level, skip int, args []interface{}) {
 		}
 	}
 	l.backend.Log(backend.Message{
-		Name:    l.name,
-		Level:   lvl,
-		Caller:  getCaller(skip + 1),
-		Message: msg,
-		Context: diag.NewContext(nil, nil),
-		Causes:  causes,
+		Name:      l.name,
+		Level:     lvl,
+		Timestamp: time.Now(),
+		Caller:    getCaller(skip + 1),
+		Message:   msg,
+		Context:   diag.NewContext(nil, nil),
+		Causes:    causes,
 	})
 }
 
@@ -211,17 +252,37 @@ func (l *Logger) logfMsgCtx(lvl Level, skip int, msg string, args []interface{})
 		}
 	}, msg, args...)
 
-	if len(rest) > 0 {
-		msg = fmt.Sprintf("%s {EXTRA_FIELDS: %v}", msg, rest)
+	extra := 0
+	offset := len(args) - len(rest)
+	for i, arg := range rest {
+		switch v := arg.(type) {
+		case diag.Field:
+			ctx.AddField(v)
+		case error:
+			// already reported as cause by ctxfmt
+		case diag.Value:
+			ctx.Add(extraKey(offset+i), v)
+			extra++
+		default:
+			ctx.AddField(diag.Any(extraKey(offset+i), arg))
+			extra++
+		}
+	}
+	if extra > 0 {
+		l.checkExtraArgs(msg, extra)
+		if l.formatCheck == FormatCheckField {
+			ctx.AddField(diag.Int("ecslog.format.extra_args", extra))
+		}
 	}
 
 	l.backend.Log(backend.Message{
-		Name:    l.name,
-		Level:   lvl,
-		Caller:  getCaller(skip + 1),
-		Message: msg,
-		Context: ctx,
-		Causes:  causes,
+		Name:      l.name,
+		Level:     lvl,
+		Timestamp: time.Now(),
+		Caller:    getCaller(skip + 1),
+		Message:   msg,
+		Context:   ctx,
+		Causes:    causes,
 	})
 }
 
@@ -233,20 +294,41 @@ func (l *Logger) logfMsg(lvl Level, skip int, msg string, args []interface{}) {
 		}
 	}, msg, args...)
 
-	if len(rest) > 0 {
-		msg = fmt.Sprintf("%s {EXTRA_FIELDS: %v}", msg, rest)
+	extra := 0
+	for _, arg := range rest {
+		switch arg.(type) {
+		case diag.Field, error:
+			// errors are already reported as cause by ctxfmt, and fields are
+			// not required, as the backend does not use the context
+		default:
+			extra++
+		}
+	}
+	if extra > 0 {
+		l.checkExtraArgs(msg, extra)
 	}
 
 	l.backend.Log(backend.Message{
-		Name:    l.name,
-		Level:   lvl,
-		Caller:  getCaller(skip + 1),
-		Message: msg,
-		Context: diag.NewContext(nil, nil),
-		Causes:  causes,
+		Name:      l.name,
+		Level:     lvl,
+		Timestamp: time.Now(),
+		Caller:    getCaller(skip + 1),
+		Message:   msg,
+		Context:   diag.NewContext(nil, nil),
+		Causes:    causes,
 	})
 }
 
+func (l *Logger) checkExtraArgs(msg string, n int) {
+	if l.formatCheck == FormatCheckPanic {
+		panic(fmt.Sprintf("ecslog: %d unused format arguments in message: %q", n, msg))
+	}
+}
+
+func extraKey(idx int) string {
+	return "extra." + strconv.FormatInt(int64(idx), 10)
+}
+
 func ensureKey(key string, idx int) string {
 	if key == "" {
 		return strconv.FormatInt(int64(idx), 10)
@@ -0,0 +1,134 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package ecslog
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/urso/diag"
+	"github.com/urso/ecslog/backend"
+)
+
+type recordBackend struct {
+	context  bool
+	messages []backend.Message
+}
+
+func (b *recordBackend) For(_ string) backend.Backend   { return b }
+func (b *recordBackend) IsEnabled(_ backend.Level) bool { return true }
+func (b *recordBackend) UseContext() bool               { return b.context }
+func (b *recordBackend) Log(msg backend.Message)        { b.messages = append(b.messages, msg) }
+func (b *recordBackend) last() backend.Message          { return b.messages[len(b.messages)-1] }
+
+func TestFormatExtraArgs(t *testing.T) {
+	rec := &recordBackend{context: true}
+	log := New(rec)
+
+	err := errors.New("oops")
+	log.Infof("value %{count}", 42, "extra", diag.String("field", "x"), err, 3.5)
+
+	msg := rec.last()
+	if msg.Message != "value 42" {
+		t.Errorf("unexpected message %q", msg.Message)
+	}
+	if len(msg.Causes) != 1 || msg.Causes[0] != err {
+		t.Errorf("expected error as cause, got %v", msg.Causes)
+	}
+
+	fields := contextFields(msg.Context)
+	expect := map[string]interface{}{
+		"count":   42,
+		"extra.1": "extra",
+		"field":   "x",
+		"extra.4": 3.5,
+	}
+	for key, want := range expect {
+		if got := fields[key]; got != want {
+			t.Errorf("%v: got %v (%T), want %v", key, got, got, want)
+		}
+	}
+	if _, exists := fields["ecslog.format.extra_args"]; exists {
+		t.Error("extra args must not be counted with FormatCheckOff")
+	}
+}
+
+func TestFormatCheck(t *testing.T) {
+	t.Run("off", func(t *testing.T) {
+		rec := &recordBackend{context: true}
+		New(rec).Infof("no args", 1)
+		if _, exists := contextFields(rec.last().Context)["ecslog.format.extra_args"]; exists {
+			t.Error("unexpected extra_args field")
+		}
+	})
+
+	t.Run("field", func(t *testing.T) {
+		rec := &recordBackend{context: true}
+		log := New(rec).WithFormatCheck(FormatCheckField)
+
+		log.Infof("no args", 1, 2, diag.String("field", "x"), errors.New("oops"))
+		if got := contextFields(rec.last().Context)["ecslog.format.extra_args"]; got != 2 {
+			t.Errorf("expected 2 extra args, got %v", got)
+		}
+
+		log.Infof("all args %v", 1)
+		if _, exists := contextFields(rec.last().Context)["ecslog.format.extra_args"]; exists {
+			t.Error("unexpected extra_args field if all args are used")
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		for _, context := range []bool{true, false} {
+			rec := &recordBackend{context: context}
+			log := New(rec).WithFormatCheck(FormatCheckPanic)
+
+			// fields and errors are not considered to be extra arguments
+			log.Infof("value %v", 1, diag.String("field", "x"), errors.New("oops"))
+
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("expected panic (context=%v)", context)
+					}
+				}()
+				log.Infof("value %v", 1, 2)
+			}()
+		}
+	})
+}
+
+func TestDerivedLoggerName(t *testing.T) {
+	rec := &recordBackend{context: true}
+	log := New(rec).Named("parent")
+
+	log.With("a", 1).Info("with")
+	log.WithFields(diag.Int("b", 2)).Info("with fields")
+	log.WithDiagnosticContext(diag.NewContext(nil, nil)).Info("with diagnostic context")
+
+	for _, msg := range rec.messages {
+		if msg.Name != "parent" {
+			t.Errorf("%v: logger name %q not propagated", msg.Message, msg.Name)
+		}
+	}
+}
+
+func contextFields(ctx *diag.Context) map[string]interface{} {
+	fields := map[string]interface{}{}
+	ctx.VisitKeyValues(visitFields(func(key string, v diag.Value) {
+		fields[key] = v.Interface()
+	}))
+	return fields
+}
+
+type visitFields func(key string, v diag.Value)
+
+func (fn visitFields) OnObjStart(_ string) error { return nil }
+func (fn visitFields) OnObjEnd() error           { return nil }
+func (fn visitFields) OnValue(key string, v diag.Value) error {
+	fn(key, v)
+	return nil
+}
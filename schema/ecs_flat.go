@@ -0,0 +1,2399 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+// Code generated by genflat from github.com/urso/diag-ecs/ecs. DO NOT EDIT.
+
+package schema
+
+// ecsFlatYML contains the ECS field definitions in the format of ECS
+// generated/ecs/ecs_flat.yml.
+const ecsFlatYML = `
+"@timestamp":
+  name: "@timestamp"
+  flat_name: "@timestamp"
+  type: date
+  description: "Date/time when the event originated. This is the date/time extracted from the event, typically representing when the event was generated by the source. If the event source has no original timestamp, this value is typically populated by the first time the event was received by the pipeline. Required field for all events."
+"agent.ephemeral_id":
+  name: "ephemeral_id"
+  flat_name: "agent.ephemeral_id"
+  type: keyword
+  description: "Ephemeral identifier of this agent (if one exists). This id normally changes across restarts, but ` + "`" + `agent.id` + "`" + ` does not."
+"agent.id":
+  name: "id"
+  flat_name: "agent.id"
+  type: keyword
+  description: "Unique identifier of this agent (if one exists). Example: For Beats this would be beat.id."
+"agent.name":
+  name: "name"
+  flat_name: "agent.name"
+  type: keyword
+  description: "Custom name of the agent. This is a name that can be given to an agent. This can be helpful if for example two Filebeat instances are running on the same host but a human readable separation is needed on which Filebeat instance data is coming from. If no name is given, the name is often left empty."
+"agent.type":
+  name: "type"
+  flat_name: "agent.type"
+  type: keyword
+  description: "Type of the agent. The agent type stays always the same and should be given by the agent used. In case of Filebeat the agent would always be Filebeat also if two Filebeat instances are run on the same machine."
+"agent.version":
+  name: "version"
+  flat_name: "agent.version"
+  type: keyword
+  description: "Version of the agent."
+"as.number":
+  name: "number"
+  flat_name: "as.number"
+  type: long
+  description: "Unique number allocated to the autonomous system. The autonomous system number (ASN) uniquely identifies each network on the Internet."
+"as.organization.name":
+  name: "name"
+  flat_name: "as.organization.name"
+  type: keyword
+  description: "Organization name."
+"client.address":
+  name: "address"
+  flat_name: "client.address"
+  type: keyword
+  description: "Some event client addresses are defined ambiguously. The event will sometimes list an IP, a domain or a unix socket.  You should always store the raw address in the ` + "`" + `.address` + "`" + ` field. Then it should be duplicated to ` + "`" + `.ip` + "`" + ` or ` + "`" + `.domain` + "`" + `, depending on which one it is."
+"client.as.number":
+  name: "number"
+  flat_name: "client.as.number"
+  type: long
+  description: "Unique number allocated to the autonomous system. The autonomous system number (ASN) uniquely identifies each network on the Internet."
+"client.as.organization.name":
+  name: "name"
+  flat_name: "client.as.organization.name"
+  type: keyword
+  description: "Organization name."
+"client.bytes":
+  name: "bytes"
+  flat_name: "client.bytes"
+  type: long
+  description: "Bytes sent from the client to the server."
+"client.domain":
+  name: "domain"
+  flat_name: "client.domain"
+  type: keyword
+  description: "Client domain."
+"client.geo.city_name":
+  name: "city_name"
+  flat_name: "client.geo.city_name"
+  type: keyword
+  description: "City name."
+"client.geo.continent_name":
+  name: "continent_name"
+  flat_name: "client.geo.continent_name"
+  type: keyword
+  description: "Name of the continent."
+"client.geo.country_iso_code":
+  name: "country_iso_code"
+  flat_name: "client.geo.country_iso_code"
+  type: keyword
+  description: "Country ISO code."
+"client.geo.country_name":
+  name: "country_name"
+  flat_name: "client.geo.country_name"
+  type: keyword
+  description: "Country name."
+"client.geo.location":
+  name: "location"
+  flat_name: "client.geo.location"
+  type: geo_point
+  description: "Longitude and latitude."
+"client.geo.name":
+  name: "name"
+  flat_name: "client.geo.name"
+  type: keyword
+  description: "User-defined description of a location, at the level of granularity they care about. Could be the name of their data centers, the floor number, if this describes a local physical entity, city names. Not typically used in automated geolocation."
+"client.geo.region_iso_code":
+  name: "region_iso_code"
+  flat_name: "client.geo.region_iso_code"
+  type: keyword
+  description: "Region ISO code."
+"client.geo.region_name":
+  name: "region_name"
+  flat_name: "client.geo.region_name"
+  type: keyword
+  description: "Region name."
+"client.ip":
+  name: "ip"
+  flat_name: "client.ip"
+  type: ip
+  description: "IP address of the client. Can be one or multiple IPv4 or IPv6 addresses."
+"client.mac":
+  name: "mac"
+  flat_name: "client.mac"
+  type: keyword
+  description: "MAC address of the client."
+"client.nat.ip":
+  name: "ip"
+  flat_name: "client.nat.ip"
+  type: ip
+  description: "Translated IP of source based NAT sessions (e.g. internal client to internet). Typically connections traversing load balancers, firewalls, or routers."
+"client.nat.port":
+  name: "port"
+  flat_name: "client.nat.port"
+  type: long
+  description: "Translated port of source based NAT sessions (e.g. internal client to internet). Typically connections traversing load balancers, firewalls, or routers."
+"client.packets":
+  name: "packets"
+  flat_name: "client.packets"
+  type: long
+  description: "Packets sent from the client to the server."
+"client.port":
+  name: "port"
+  flat_name: "client.port"
+  type: long
+  description: "Port of the client."
+"client.registered_domain":
+  name: "registered_domain"
+  flat_name: "client.registered_domain"
+  type: keyword
+  description: "The highest registered client domain, stripped of the subdomain. For example, the registered domain for \"foo.google.com\" is \"google.com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last two labels will not work well for TLDs such as \"co.uk\"."
+"client.top_level_domain":
+  name: "top_level_domain"
+  flat_name: "client.top_level_domain"
+  type: keyword
+  description: "The effective top level domain (eTLD), also known as the domain suffix, is the last part of the domain name. For example, the top level domain for google.com is \"com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last label will not work well for effective TLDs such as \"co.uk\"."
+"client.user.domain":
+  name: "domain"
+  flat_name: "client.user.domain"
+  type: keyword
+  description: "Name of the directory the user is a member of. For example, an LDAP or Active Directory domain name."
+"client.user.email":
+  name: "email"
+  flat_name: "client.user.email"
+  type: keyword
+  description: "User email address."
+"client.user.full_name":
+  name: "full_name"
+  flat_name: "client.user.full_name"
+  type: keyword
+  description: "User's full name, if available."
+"client.user.group.domain":
+  name: "domain"
+  flat_name: "client.user.group.domain"
+  type: keyword
+  description: "Name of the directory the group is a member of. For example, an LDAP or Active Directory domain name."
+"client.user.group.id":
+  name: "id"
+  flat_name: "client.user.group.id"
+  type: keyword
+  description: "Unique identifier for the group on the system/platform."
+"client.user.group.name":
+  name: "name"
+  flat_name: "client.user.group.name"
+  type: keyword
+  description: "Name of the group."
+"client.user.hash":
+  name: "hash"
+  flat_name: "client.user.hash"
+  type: keyword
+  description: "Unique user hash to correlate information for a user in anonymized form. Useful if ` + "`" + `user.id` + "`" + ` or ` + "`" + `user.name` + "`" + ` contain confidential information and cannot be used."
+"client.user.id":
+  name: "id"
+  flat_name: "client.user.id"
+  type: keyword
+  description: "One or multiple unique identifiers of the user."
+"client.user.name":
+  name: "name"
+  flat_name: "client.user.name"
+  type: keyword
+  description: "Short name or login of the user."
+"cloud.account.id":
+  name: "id"
+  flat_name: "cloud.account.id"
+  type: keyword
+  description: "The cloud account or organization id used to identify different entities in a multi-tenant environment. Examples: AWS account id, Google Cloud ORG Id, or other unique identifier."
+"cloud.availability_zone":
+  name: "availability_zone"
+  flat_name: "cloud.availability_zone"
+  type: keyword
+  description: "Availability zone in which this host is running."
+"cloud.instance.id":
+  name: "id"
+  flat_name: "cloud.instance.id"
+  type: keyword
+  description: "Instance ID of the host machine."
+"cloud.instance.name":
+  name: "name"
+  flat_name: "cloud.instance.name"
+  type: keyword
+  description: "Instance name of the host machine."
+"cloud.machine.type":
+  name: "type"
+  flat_name: "cloud.machine.type"
+  type: keyword
+  description: "Machine type of the host machine."
+"cloud.provider":
+  name: "provider"
+  flat_name: "cloud.provider"
+  type: keyword
+  description: "Name of the cloud provider. Example values are aws, azure, gcp, or digitalocean."
+"cloud.region":
+  name: "region"
+  flat_name: "cloud.region"
+  type: keyword
+  description: "Region in which this host is running."
+"container.id":
+  name: "id"
+  flat_name: "container.id"
+  type: keyword
+  description: "Unique container id."
+"container.image.name":
+  name: "name"
+  flat_name: "container.image.name"
+  type: keyword
+  description: "Name of the image the container was built on."
+"container.image.tag":
+  name: "tag"
+  flat_name: "container.image.tag"
+  type: keyword
+  description: "Container image tag."
+"container.labels":
+  name: "labels"
+  flat_name: "container.labels"
+  type: object
+  description: "Image labels."
+"container.name":
+  name: "name"
+  flat_name: "container.name"
+  type: keyword
+  description: "Container name."
+"container.runtime":
+  name: "runtime"
+  flat_name: "container.runtime"
+  type: keyword
+  description: "Runtime managing this container."
+"destination.address":
+  name: "address"
+  flat_name: "destination.address"
+  type: keyword
+  description: "Some event destination addresses are defined ambiguously. The event will sometimes list an IP, a domain or a unix socket.  You should always store the raw address in the ` + "`" + `.address` + "`" + ` field. Then it should be duplicated to ` + "`" + `.ip` + "`" + ` or ` + "`" + `.domain` + "`" + `, depending on which one it is."
+"destination.as.number":
+  name: "number"
+  flat_name: "destination.as.number"
+  type: long
+  description: "Unique number allocated to the autonomous system. The autonomous system number (ASN) uniquely identifies each network on the Internet."
+"destination.as.organization.name":
+  name: "name"
+  flat_name: "destination.as.organization.name"
+  type: keyword
+  description: "Organization name."
+"destination.bytes":
+  name: "bytes"
+  flat_name: "destination.bytes"
+  type: long
+  description: "Bytes sent from the destination to the source."
+"destination.domain":
+  name: "domain"
+  flat_name: "destination.domain"
+  type: keyword
+  description: "Destination domain."
+"destination.geo.city_name":
+  name: "city_name"
+  flat_name: "destination.geo.city_name"
+  type: keyword
+  description: "City name."
+"destination.geo.continent_name":
+  name: "continent_name"
+  flat_name: "destination.geo.continent_name"
+  type: keyword
+  description: "Name of the continent."
+"destination.geo.country_iso_code":
+  name: "country_iso_code"
+  flat_name: "destination.geo.country_iso_code"
+  type: keyword
+  description: "Country ISO code."
+"destination.geo.country_name":
+  name: "country_name"
+  flat_name: "destination.geo.country_name"
+  type: keyword
+  description: "Country name."
+"destination.geo.location":
+  name: "location"
+  flat_name: "destination.geo.location"
+  type: geo_point
+  description: "Longitude and latitude."
+"destination.geo.name":
+  name: "name"
+  flat_name: "destination.geo.name"
+  type: keyword
+  description: "User-defined description of a location, at the level of granularity they care about. Could be the name of their data centers, the floor number, if this describes a local physical entity, city names. Not typically used in automated geolocation."
+"destination.geo.region_iso_code":
+  name: "region_iso_code"
+  flat_name: "destination.geo.region_iso_code"
+  type: keyword
+  description: "Region ISO code."
+"destination.geo.region_name":
+  name: "region_name"
+  flat_name: "destination.geo.region_name"
+  type: keyword
+  description: "Region name."
+"destination.ip":
+  name: "ip"
+  flat_name: "destination.ip"
+  type: ip
+  description: "IP address of the destination. Can be one or multiple IPv4 or IPv6 addresses."
+"destination.mac":
+  name: "mac"
+  flat_name: "destination.mac"
+  type: keyword
+  description: "MAC address of the destination."
+"destination.nat.ip":
+  name: "ip"
+  flat_name: "destination.nat.ip"
+  type: ip
+  description: "Translated ip of destination based NAT sessions (e.g. internet to private DMZ) Typically used with load balancers, firewalls, or routers."
+"destination.nat.port":
+  name: "port"
+  flat_name: "destination.nat.port"
+  type: long
+  description: "Port the source session is translated to by NAT Device. Typically used with load balancers, firewalls, or routers."
+"destination.packets":
+  name: "packets"
+  flat_name: "destination.packets"
+  type: long
+  description: "Packets sent from the destination to the source."
+"destination.port":
+  name: "port"
+  flat_name: "destination.port"
+  type: long
+  description: "Port of the destination."
+"destination.registered_domain":
+  name: "registered_domain"
+  flat_name: "destination.registered_domain"
+  type: keyword
+  description: "The highest registered destination domain, stripped of the subdomain. For example, the registered domain for \"foo.google.com\" is \"google.com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last two labels will not work well for TLDs such as \"co.uk\"."
+"destination.top_level_domain":
+  name: "top_level_domain"
+  flat_name: "destination.top_level_domain"
+  type: keyword
+  description: "The effective top level domain (eTLD), also known as the domain suffix, is the last part of the domain name. For example, the top level domain for google.com is \"com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last label will not work well for effective TLDs such as \"co.uk\"."
+"destination.user.domain":
+  name: "domain"
+  flat_name: "destination.user.domain"
+  type: keyword
+  description: "Name of the directory the user is a member of. For example, an LDAP or Active Directory domain name."
+"destination.user.email":
+  name: "email"
+  flat_name: "destination.user.email"
+  type: keyword
+  description: "User email address."
+"destination.user.full_name":
+  name: "full_name"
+  flat_name: "destination.user.full_name"
+  type: keyword
+  description: "User's full name, if available."
+"destination.user.group.domain":
+  name: "domain"
+  flat_name: "destination.user.group.domain"
+  type: keyword
+  description: "Name of the directory the group is a member of. For example, an LDAP or Active Directory domain name."
+"destination.user.group.id":
+  name: "id"
+  flat_name: "destination.user.group.id"
+  type: keyword
+  description: "Unique identifier for the group on the system/platform."
+"destination.user.group.name":
+  name: "name"
+  flat_name: "destination.user.group.name"
+  type: keyword
+  description: "Name of the group."
+"destination.user.hash":
+  name: "hash"
+  flat_name: "destination.user.hash"
+  type: keyword
+  description: "Unique user hash to correlate information for a user in anonymized form. Useful if ` + "`" + `user.id` + "`" + ` or ` + "`" + `user.name` + "`" + ` contain confidential information and cannot be used."
+"destination.user.id":
+  name: "id"
+  flat_name: "destination.user.id"
+  type: keyword
+  description: "One or multiple unique identifiers of the user."
+"destination.user.name":
+  name: "name"
+  flat_name: "destination.user.name"
+  type: keyword
+  description: "Short name or login of the user."
+"dns.answers.class":
+  name: "class"
+  flat_name: "dns.answers.class"
+  type: keyword
+  description: "The class of DNS data contained in this resource record."
+"dns.answers.data":
+  name: "data"
+  flat_name: "dns.answers.data"
+  type: keyword
+  description: "The data describing the resource. The meaning of this data depends on the type and class of the resource record."
+"dns.answers.name":
+  name: "name"
+  flat_name: "dns.answers.name"
+  type: keyword
+  description: "The domain name to which this resource record pertains. If a chain of CNAME is being resolved, each answer's ` + "`" + `name` + "`" + ` should be the one that corresponds with the answer's ` + "`" + `data` + "`" + `. It should not simply be the original ` + "`" + `question.name` + "`" + ` repeated."
+"dns.answers.ttl":
+  name: "ttl"
+  flat_name: "dns.answers.ttl"
+  type: long
+  description: "The time interval in seconds that this resource record may be cached before it should be discarded. Zero values mean that the data should not be cached."
+"dns.answers.type":
+  name: "type"
+  flat_name: "dns.answers.type"
+  type: keyword
+  description: "The type of data contained in this resource record."
+"dns.header_flags":
+  name: "header_flags"
+  flat_name: "dns.header_flags"
+  type: keyword
+  description: "Array of 2 letter DNS header flags. Expected values are: AA, TC, RD, RA, AD, CD, DO."
+"dns.id":
+  name: "id"
+  flat_name: "dns.id"
+  type: keyword
+  description: "The DNS packet identifier assigned by the program that generated the query. The identifier is copied to the response."
+"dns.op_code":
+  name: "op_code"
+  flat_name: "dns.op_code"
+  type: keyword
+  description: "The DNS operation code that specifies the kind of query in the message. This value is set by the originator of a query and copied into the response."
+"dns.question.class":
+  name: "class"
+  flat_name: "dns.question.class"
+  type: keyword
+  description: "The class of records being queried."
+"dns.question.name":
+  name: "name"
+  flat_name: "dns.question.name"
+  type: keyword
+  description: "The name being queried. If the name field contains non-printable characters (below 32 or above 126), those characters should be represented as escaped base 10 integers (\\DDD). Back slashes and quotes should be escaped. Tabs, carriage returns, and line feeds should be converted to \\t, \\r, and \\n respectively."
+"dns.question.registered_domain":
+  name: "registered_domain"
+  flat_name: "dns.question.registered_domain"
+  type: keyword
+  description: "The highest registered domain, stripped of the subdomain. For example, the registered domain for \"foo.google.com\" is \"google.com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last two labels will not work well for TLDs such as \"co.uk\"."
+"dns.question.subdomain":
+  name: "subdomain"
+  flat_name: "dns.question.subdomain"
+  type: keyword
+  description: "The subdomain is all of the labels under the registered_domain. If the domain has multiple levels of subdomain, such as \"sub2.sub1.example.com\", the subdomain field should contain \"sub2.sub1\", with no trailing period."
+"dns.question.top_level_domain":
+  name: "top_level_domain"
+  flat_name: "dns.question.top_level_domain"
+  type: keyword
+  description: "The effective top level domain (eTLD), also known as the domain suffix, is the last part of the domain name. For example, the top level domain for google.com is \"com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last label will not work well for effective TLDs such as \"co.uk\"."
+"dns.question.type":
+  name: "type"
+  flat_name: "dns.question.type"
+  type: keyword
+  description: "The type of record being queried."
+"dns.resolved_ip":
+  name: "resolved_ip"
+  flat_name: "dns.resolved_ip"
+  type: keyword
+  description: "Array containing all IPs seen in ` + "`" + `answers.data` + "`" + `. The ` + "`" + `answers` + "`" + ` array can be difficult to use, because of the variety of data formats it can contain. Extracting all IP addresses seen in there to ` + "`" + `dns.resolved_ip` + "`" + ` makes it possible to index them as IP addresses, and makes them easier to visualize and query for."
+"dns.response_code":
+  name: "response_code"
+  flat_name: "dns.response_code"
+  type: keyword
+  description: "The DNS response code."
+"dns.type":
+  name: "type"
+  flat_name: "dns.type"
+  type: keyword
+  description: "The type of DNS event captured, query or answer. If your source of DNS events only gives you DNS queries, you should only create dns events of type ` + "`" + `dns.type:query` + "`" + `. If your source of DNS events gives you answers as well, you should create one event per query (optionally as soon as the query is seen). And a second event containing all query details as well as an array of answers."
+"ecs.version":
+  name: "version"
+  flat_name: "ecs.version"
+  type: keyword
+  description: "ECS version this event conforms to. ` + "`" + `ecs.version` + "`" + ` is a required field and must exist in all events. When querying across multiple indices -- which may conform to slightly different ECS versions -- this field lets integrations adjust to the schema version of the events."
+"error.code":
+  name: "code"
+  flat_name: "error.code"
+  type: keyword
+  description: "Error code describing the error."
+"error.id":
+  name: "id"
+  flat_name: "error.id"
+  type: keyword
+  description: "Unique identifier for the error."
+"error.message":
+  name: "message"
+  flat_name: "error.message"
+  type: text
+  description: "Error message."
+"error.stack_trace":
+  name: "stack_trace"
+  flat_name: "error.stack_trace"
+  type: keyword
+  description: "The stack trace of this error in plain text."
+"error.type":
+  name: "type"
+  flat_name: "error.type"
+  type: keyword
+  description: "The type of the error, for example the class name of the exception."
+"event.action":
+  name: "action"
+  flat_name: "event.action"
+  type: keyword
+  description: "The action captured by the event. This describes the information in the event. It is more specific than ` + "`" + `event.category` + "`" + `. Examples are ` + "`" + `group-add` + "`" + `, ` + "`" + `process-started` + "`" + `, ` + "`" + `file-created` + "`" + `. The value is normally defined by the implementer."
+"event.category":
+  name: "category"
+  flat_name: "event.category"
+  type: keyword
+  description: "This is one of four ECS Categorization Fields, and indicates the second level in the ECS category hierarchy. ` + "`" + `event.category` + "`" + ` represents the \"big buckets\" of ECS categories. For example, filtering on ` + "`" + `event.category:process` + "`" + ` yields all events relating to process activity. This field is closely related to ` + "`" + `event.type` + "`" + `, which is used as a subcategory. This field is an array. This will allow proper categorization of some events that fall in multiple categories."
+"event.code":
+  name: "code"
+  flat_name: "event.code"
+  type: keyword
+  description: "Identification code for this event, if one exists. Some event sources use event codes to identify messages unambiguously, regardless of message language or wording adjustments over time. An example of this is the Windows Event ID."
+"event.created":
+  name: "created"
+  flat_name: "event.created"
+  type: date
+  description: "event.created contains the date/time when the event was first read by an agent, or by your pipeline. This field is distinct from @timestamp in that @timestamp typically contain the time extracted from the original event. In most situations, these two timestamps will be slightly different. The difference can be used to calculate the delay between your source generating an event, and the time when your agent first processed it. This can be used to monitor your agent's or pipeline's ability to keep up with your event source. In case the two timestamps are identical, @timestamp should be used."
+"event.dataset":
+  name: "dataset"
+  flat_name: "event.dataset"
+  type: keyword
+  description: "Name of the dataset. If an event source publishes more than one type of log or events (e.g. access log, error log), the dataset is used to specify which one the event comes from. It's recommended but not required to start the dataset name with the module name, followed by a dot, then the dataset name."
+"event.duration":
+  name: "duration"
+  flat_name: "event.duration"
+  type: long
+  description: "Duration of the event in nanoseconds. If event.start and event.end are known this value should be the difference between the end and start time."
+"event.end":
+  name: "end"
+  flat_name: "event.end"
+  type: date
+  description: "event.end contains the date when the event ended or when the activity was last observed."
+"event.hash":
+  name: "hash"
+  flat_name: "event.hash"
+  type: keyword
+  description: "Hash (perhaps logstash fingerprint) of raw field to be able to demonstrate log integrity."
+"event.id":
+  name: "id"
+  flat_name: "event.id"
+  type: keyword
+  description: "Unique ID to describe the event."
+"event.ingested":
+  name: "ingested"
+  flat_name: "event.ingested"
+  type: date
+  description: "Timestamp when an event arrived in the central data store. This is different from ` + "`" + `@timestamp` + "`" + `, which is when the event originally occurred.  It's also different from ` + "`" + `event.created` + "`" + `, which is meant to capture the first time an agent saw the event. In normal conditions, assuming no tampering, the timestamps should chronologically look like this: ` + "`" + `@timestamp` + "`" + ` < ` + "`" + `event.created` + "`" + ` < ` + "`" + `event.ingested` + "`" + `."
+"event.kind":
+  name: "kind"
+  flat_name: "event.kind"
+  type: keyword
+  description: "This is one of four ECS Categorization Fields, and indicates the highest level in the ECS category hierarchy. ` + "`" + `event.kind` + "`" + ` gives high-level information about what type of information the event contains, without being specific to the contents of the event. For example, values of this field distinguish alert events from metric events. The value of this field can be used to inform how these kinds of events should be handled. They may warrant different retention, different access control, it may also help understand whether the data coming in at a regular interval or not."
+"event.module":
+  name: "module"
+  flat_name: "event.module"
+  type: keyword
+  description: "Name of the module this data is coming from. If your monitoring agent supports the concept of modules or plugins to process events of a given source (e.g. Apache logs), ` + "`" + `event.module` + "`" + ` should contain the name of this module."
+"event.original":
+  name: "original"
+  flat_name: "event.original"
+  type: keyword
+  description: "Raw text message of entire event. Used to demonstrate log integrity. This field is not indexed and doc_values are disabled. It cannot be searched, but it can be retrieved from ` + "`" + `_source` + "`" + `."
+"event.outcome":
+  name: "outcome"
+  flat_name: "event.outcome"
+  type: keyword
+  description: "This is one of four ECS Categorization Fields, and indicates the lowest level in the ECS category hierarchy. ` + "`" + `event.outcome` + "`" + ` simply denotes whether the event represent a success or a failure. Note that not all events will have an associated outcome. For example, this field is generally not populated for metric events or events with ` + "`" + `event.type:info` + "`" + `."
+"event.provider":
+  name: "provider"
+  flat_name: "event.provider"
+  type: keyword
+  description: "Source of the event. Event transports such as Syslog or the Windows Event Log typically mention the source of an event. It can be the name of the software that generated the event (e.g. Sysmon, httpd), or of a subsystem of the operating system (kernel, Microsoft-Windows-Security-Auditing)."
+"event.risk_score":
+  name: "risk_score"
+  flat_name: "event.risk_score"
+  type: float
+  description: "Risk score or priority of the event (e.g. security solutions). Use your system's original value here."
+"event.risk_score_norm":
+  name: "risk_score_norm"
+  flat_name: "event.risk_score_norm"
+  type: float
+  description: "Normalized risk score or priority of the event, on a scale of 0 to 100. This is mainly useful if you use more than one system that assigns risk scores, and you want to see a normalized value across all systems."
+"event.sequence":
+  name: "sequence"
+  flat_name: "event.sequence"
+  type: long
+  description: "Sequence number of the event. The sequence number is a value published by some event sources, to make the exact ordering of events unambiguous, regarless of the timestamp precision."
+"event.severity":
+  name: "severity"
+  flat_name: "event.severity"
+  type: long
+  description: "The numeric severity of the event according to your event source. What the different severity values mean can be different between sources and use cases. It's up to the implementer to make sure severities are consistent across events from the same source. The Syslog severity belongs in ` + "`" + `log.syslog.severity.code` + "`" + `. ` + "`" + `event.severity` + "`" + ` is meant to represent the severity according to the event source (e.g. firewall, IDS). If the event source does not publish its own severity, you may optionally copy the ` + "`" + `log.syslog.severity.code` + "`" + ` to ` + "`" + `event.severity` + "`" + `."
+"event.start":
+  name: "start"
+  flat_name: "event.start"
+  type: date
+  description: "event.start contains the date when the event started or when the activity was first observed."
+"event.timezone":
+  name: "timezone"
+  flat_name: "event.timezone"
+  type: keyword
+  description: "This field should be populated when the event's timestamp does not include timezone information already (e.g. default Syslog timestamps). It's optional otherwise. Acceptable timezone formats are: a canonical ID (e.g. \"Europe/Amsterdam\"), abbreviated (e.g. \"EST\") or an HH:mm differential (e.g. \"-05:00\")."
+"event.type":
+  name: "type"
+  flat_name: "event.type"
+  type: keyword
+  description: "This is one of four ECS Categorization Fields, and indicates the third level in the ECS category hierarchy. ` + "`" + `event.type` + "`" + ` represents a categorization \"sub-bucket\" that, when used along with the ` + "`" + `event.category` + "`" + ` field values, enables filtering events down to a level appropriate for single visualization. This field is an array. This will allow proper categorization of some events that fall in multiple event types."
+"file.accessed":
+  name: "accessed"
+  flat_name: "file.accessed"
+  type: date
+  description: "Last time the file was accessed. Note that not all filesystems keep track of access time."
+"file.attributes":
+  name: "attributes"
+  flat_name: "file.attributes"
+  type: keyword
+  description: "Array of file attributes. Attributes names will vary by platform. Here's a non-exhaustive list of values that are expected in this field: archive, compressed, directory, encrypted, execute, hidden, read, readonly, system, write."
+"file.created":
+  name: "created"
+  flat_name: "file.created"
+  type: date
+  description: "File creation time. Note that not all filesystems store the creation time."
+"file.ctime":
+  name: "ctime"
+  flat_name: "file.ctime"
+  type: date
+  description: "Last time the file attributes or metadata changed. Note that changes to the file content will update ` + "`" + `mtime` + "`" + `. This implies ` + "`" + `ctime` + "`" + ` will be adjusted at the same time, since ` + "`" + `mtime` + "`" + ` is an attribute of the file."
+"file.device":
+  name: "device"
+  flat_name: "file.device"
+  type: keyword
+  description: "Device that is the source of the file."
+"file.directory":
+  name: "directory"
+  flat_name: "file.directory"
+  type: keyword
+  description: "Directory where the file is located. It should include the drive letter, when appropriate."
+"file.drive_letter":
+  name: "drive_letter"
+  flat_name: "file.drive_letter"
+  type: keyword
+  description: "Drive letter where the file is located. This field is only relevant on Windows. The value should be uppercase, and not include the colon."
+"file.extension":
+  name: "extension"
+  flat_name: "file.extension"
+  type: keyword
+  description: "File extension."
+"file.gid":
+  name: "gid"
+  flat_name: "file.gid"
+  type: keyword
+  description: "Primary group ID (GID) of the file."
+"file.group":
+  name: "group"
+  flat_name: "file.group"
+  type: keyword
+  description: "Primary group name of the file."
+"file.hash.md5":
+  name: "md5"
+  flat_name: "file.hash.md5"
+  type: keyword
+  description: "MD5 hash."
+"file.hash.sha1":
+  name: "sha1"
+  flat_name: "file.hash.sha1"
+  type: keyword
+  description: "SHA1 hash."
+"file.hash.sha256":
+  name: "sha256"
+  flat_name: "file.hash.sha256"
+  type: keyword
+  description: "SHA256 hash."
+"file.hash.sha512":
+  name: "sha512"
+  flat_name: "file.hash.sha512"
+  type: keyword
+  description: "SHA512 hash."
+"file.inode":
+  name: "inode"
+  flat_name: "file.inode"
+  type: keyword
+  description: "Inode representing the file in the filesystem."
+"file.mode":
+  name: "mode"
+  flat_name: "file.mode"
+  type: keyword
+  description: "Mode of the file in octal representation."
+"file.mtime":
+  name: "mtime"
+  flat_name: "file.mtime"
+  type: date
+  description: "Last time the file content was modified."
+"file.name":
+  name: "name"
+  flat_name: "file.name"
+  type: keyword
+  description: "Name of the file including the extension, without the directory."
+"file.owner":
+  name: "owner"
+  flat_name: "file.owner"
+  type: keyword
+  description: "File owner's username."
+"file.path":
+  name: "path"
+  flat_name: "file.path"
+  type: keyword
+  description: "Full path to the file, including the file name. It should include the drive letter, when appropriate."
+"file.size":
+  name: "size"
+  flat_name: "file.size"
+  type: long
+  description: "File size in bytes. Only relevant when ` + "`" + `file.type` + "`" + ` is \"file\"."
+"file.target_path":
+  name: "target_path"
+  flat_name: "file.target_path"
+  type: keyword
+  description: "Target path for symlinks."
+"file.type":
+  name: "type"
+  flat_name: "file.type"
+  type: keyword
+  description: "File type (file, dir, or symlink)."
+"file.uid":
+  name: "uid"
+  flat_name: "file.uid"
+  type: keyword
+  description: "The user ID (UID) or security identifier (SID) of the file owner."
+"geo.city_name":
+  name: "city_name"
+  flat_name: "geo.city_name"
+  type: keyword
+  description: "City name."
+"geo.continent_name":
+  name: "continent_name"
+  flat_name: "geo.continent_name"
+  type: keyword
+  description: "Name of the continent."
+"geo.country_iso_code":
+  name: "country_iso_code"
+  flat_name: "geo.country_iso_code"
+  type: keyword
+  description: "Country ISO code."
+"geo.country_name":
+  name: "country_name"
+  flat_name: "geo.country_name"
+  type: keyword
+  description: "Country name."
+"geo.location":
+  name: "location"
+  flat_name: "geo.location"
+  type: geo_point
+  description: "Longitude and latitude."
+"geo.name":
+  name: "name"
+  flat_name: "geo.name"
+  type: keyword
+  description: "User-defined description of a location, at the level of granularity they care about. Could be the name of their data centers, the floor number, if this describes a local physical entity, city names. Not typically used in automated geolocation."
+"geo.region_iso_code":
+  name: "region_iso_code"
+  flat_name: "geo.region_iso_code"
+  type: keyword
+  description: "Region ISO code."
+"geo.region_name":
+  name: "region_name"
+  flat_name: "geo.region_name"
+  type: keyword
+  description: "Region name."
+"group.domain":
+  name: "domain"
+  flat_name: "group.domain"
+  type: keyword
+  description: "Name of the directory the group is a member of. For example, an LDAP or Active Directory domain name."
+"group.id":
+  name: "id"
+  flat_name: "group.id"
+  type: keyword
+  description: "Unique identifier for the group on the system/platform."
+"group.name":
+  name: "name"
+  flat_name: "group.name"
+  type: keyword
+  description: "Name of the group."
+"hash.md5":
+  name: "md5"
+  flat_name: "hash.md5"
+  type: keyword
+  description: "MD5 hash."
+"hash.sha1":
+  name: "sha1"
+  flat_name: "hash.sha1"
+  type: keyword
+  description: "SHA1 hash."
+"hash.sha256":
+  name: "sha256"
+  flat_name: "hash.sha256"
+  type: keyword
+  description: "SHA256 hash."
+"hash.sha512":
+  name: "sha512"
+  flat_name: "hash.sha512"
+  type: keyword
+  description: "SHA512 hash."
+"host.architecture":
+  name: "architecture"
+  flat_name: "host.architecture"
+  type: keyword
+  description: "Operating system architecture."
+"host.domain":
+  name: "domain"
+  flat_name: "host.domain"
+  type: keyword
+  description: "Name of the domain of which the host is a member.  For example, on Windows this could be the host's Active Directory domain or NetBIOS domain name.  For Linux this could be the domain of the host's LDAP provider."
+"host.geo.city_name":
+  name: "city_name"
+  flat_name: "host.geo.city_name"
+  type: keyword
+  description: "City name."
+"host.geo.continent_name":
+  name: "continent_name"
+  flat_name: "host.geo.continent_name"
+  type: keyword
+  description: "Name of the continent."
+"host.geo.country_iso_code":
+  name: "country_iso_code"
+  flat_name: "host.geo.country_iso_code"
+  type: keyword
+  description: "Country ISO code."
+"host.geo.country_name":
+  name: "country_name"
+  flat_name: "host.geo.country_name"
+  type: keyword
+  description: "Country name."
+"host.geo.location":
+  name: "location"
+  flat_name: "host.geo.location"
+  type: geo_point
+  description: "Longitude and latitude."
+"host.geo.name":
+  name: "name"
+  flat_name: "host.geo.name"
+  type: keyword
+  description: "User-defined description of a location, at the level of granularity they care about. Could be the name of their data centers, the floor number, if this describes a local physical entity, city names. Not typically used in automated geolocation."
+"host.geo.region_iso_code":
+  name: "region_iso_code"
+  flat_name: "host.geo.region_iso_code"
+  type: keyword
+  description: "Region ISO code."
+"host.geo.region_name":
+  name: "region_name"
+  flat_name: "host.geo.region_name"
+  type: keyword
+  description: "Region name."
+"host.hostname":
+  name: "hostname"
+  flat_name: "host.hostname"
+  type: keyword
+  description: "Hostname of the host. It normally contains what the ` + "`" + `hostname` + "`" + ` command returns on the host machine."
+"host.id":
+  name: "id"
+  flat_name: "host.id"
+  type: keyword
+  description: "Unique host id. As hostname is not always unique, use values that are meaningful in your environment. Example: The current usage of ` + "`" + `beat.name` + "`" + `."
+"host.ip":
+  name: "ip"
+  flat_name: "host.ip"
+  type: ip
+  description: "Host ip address."
+"host.mac":
+  name: "mac"
+  flat_name: "host.mac"
+  type: keyword
+  description: "Host mac address."
+"host.name":
+  name: "name"
+  flat_name: "host.name"
+  type: keyword
+  description: "Name of the host. It can contain what ` + "`" + `hostname` + "`" + ` returns on Unix systems, the fully qualified domain name, or a name specified by the user. The sender decides which value to use."
+"host.os.family":
+  name: "family"
+  flat_name: "host.os.family"
+  type: keyword
+  description: "OS family (such as redhat, debian, freebsd, windows)."
+"host.os.full":
+  name: "full"
+  flat_name: "host.os.full"
+  type: keyword
+  description: "Operating system name, including the version or code name."
+"host.os.kernel":
+  name: "kernel"
+  flat_name: "host.os.kernel"
+  type: keyword
+  description: "Operating system kernel version as a raw string."
+"host.os.name":
+  name: "name"
+  flat_name: "host.os.name"
+  type: keyword
+  description: "Operating system name, without the version."
+"host.os.platform":
+  name: "platform"
+  flat_name: "host.os.platform"
+  type: keyword
+  description: "Operating system platform (such centos, ubuntu, windows)."
+"host.os.version":
+  name: "version"
+  flat_name: "host.os.version"
+  type: keyword
+  description: "Operating system version as a raw string."
+"host.type":
+  name: "type"
+  flat_name: "host.type"
+  type: keyword
+  description: "Type of host. For Cloud providers this can be the machine type like ` + "`" + `t2.medium` + "`" + `. If vm, this could be the container, for example, or other information meaningful in your environment."
+"host.uptime":
+  name: "uptime"
+  flat_name: "host.uptime"
+  type: long
+  description: "Seconds the host has been up."
+"host.user.domain":
+  name: "domain"
+  flat_name: "host.user.domain"
+  type: keyword
+  description: "Name of the directory the user is a member of. For example, an LDAP or Active Directory domain name."
+"host.user.email":
+  name: "email"
+  flat_name: "host.user.email"
+  type: keyword
+  description: "User email address."
+"host.user.full_name":
+  name: "full_name"
+  flat_name: "host.user.full_name"
+  type: keyword
+  description: "User's full name, if available."
+"host.user.group.domain":
+  name: "domain"
+  flat_name: "host.user.group.domain"
+  type: keyword
+  description: "Name of the directory the group is a member of. For example, an LDAP or Active Directory domain name."
+"host.user.group.id":
+  name: "id"
+  flat_name: "host.user.group.id"
+  type: keyword
+  description: "Unique identifier for the group on the system/platform."
+"host.user.group.name":
+  name: "name"
+  flat_name: "host.user.group.name"
+  type: keyword
+  description: "Name of the group."
+"host.user.hash":
+  name: "hash"
+  flat_name: "host.user.hash"
+  type: keyword
+  description: "Unique user hash to correlate information for a user in anonymized form. Useful if ` + "`" + `user.id` + "`" + ` or ` + "`" + `user.name` + "`" + ` contain confidential information and cannot be used."
+"host.user.id":
+  name: "id"
+  flat_name: "host.user.id"
+  type: keyword
+  description: "One or multiple unique identifiers of the user."
+"host.user.name":
+  name: "name"
+  flat_name: "host.user.name"
+  type: keyword
+  description: "Short name or login of the user."
+"http.request.body.bytes":
+  name: "bytes"
+  flat_name: "http.request.body.bytes"
+  type: long
+  description: "Size in bytes of the request body."
+"http.request.body.content":
+  name: "content"
+  flat_name: "http.request.body.content"
+  type: keyword
+  description: "The full HTTP request body."
+"http.request.bytes":
+  name: "bytes"
+  flat_name: "http.request.bytes"
+  type: long
+  description: "Total size in bytes of the request (body and headers)."
+"http.request.method":
+  name: "method"
+  flat_name: "http.request.method"
+  type: keyword
+  description: "HTTP request method. The field value must be normalized to lowercase for querying. See the documentation section \"Implementing ECS\"."
+"http.request.referrer":
+  name: "referrer"
+  flat_name: "http.request.referrer"
+  type: keyword
+  description: "Referrer for this HTTP request."
+"http.response.body.bytes":
+  name: "bytes"
+  flat_name: "http.response.body.bytes"
+  type: long
+  description: "Size in bytes of the response body."
+"http.response.body.content":
+  name: "content"
+  flat_name: "http.response.body.content"
+  type: keyword
+  description: "The full HTTP response body."
+"http.response.bytes":
+  name: "bytes"
+  flat_name: "http.response.bytes"
+  type: long
+  description: "Total size in bytes of the response (body and headers)."
+"http.response.status_code":
+  name: "status_code"
+  flat_name: "http.response.status_code"
+  type: long
+  description: "HTTP response status code."
+"http.version":
+  name: "version"
+  flat_name: "http.version"
+  type: keyword
+  description: "HTTP version."
+"labels":
+  name: "labels"
+  flat_name: "labels"
+  type: object
+  description: "Custom key/value pairs. Can be used to add meta information to events. Should not contain nested objects. All values are stored as keyword."
+"log.level":
+  name: "level"
+  flat_name: "log.level"
+  type: keyword
+  description: "Original log level of the log event. If the source of the event provides a log level or textual severity, this is the one that goes in ` + "`" + `log.level` + "`" + `. If your source doesn't specify one, you may put your event transport's severity here (e.g. Syslog severity). Some examples are ` + "`" + `warn` + "`" + `, ` + "`" + `err` + "`" + `, ` + "`" + `i` + "`" + `, ` + "`" + `informational` + "`" + `."
+"log.logger":
+  name: "logger"
+  flat_name: "log.logger"
+  type: keyword
+  description: "The name of the logger inside an application. This is usually the name of the class which initialized the logger, or can be a custom name."
+"log.origin.file.line":
+  name: "line"
+  flat_name: "log.origin.file.line"
+  type: integer
+  description: "The line number of the file containing the source code which originated the log event."
+"log.origin.file.name":
+  name: "name"
+  flat_name: "log.origin.file.name"
+  type: keyword
+  description: "The name of the file containing the source code which originated the log event. Note that this is not the name of the log file."
+"log.origin.function":
+  name: "function"
+  flat_name: "log.origin.function"
+  type: keyword
+  description: "The name of the function or method which originated the log event."
+"log.original":
+  name: "original"
+  flat_name: "log.original"
+  type: keyword
+  description: "This is the original log message and contains the full log message before splitting it up in multiple parts. In contrast to the ` + "`" + `message` + "`" + ` field which can contain an extracted part of the log message, this field contains the original, full log message. It can have already some modifications applied like encoding or new lines removed to clean up the log message. This field is not indexed and doc_values are disabled so it can't be queried but the value can be retrieved from ` + "`" + `_source` + "`" + `."
+"log.syslog.facility.code":
+  name: "code"
+  flat_name: "log.syslog.facility.code"
+  type: long
+  description: "The Syslog numeric facility of the log event, if available. According to RFCs 5424 and 3164, this value should be an integer between 0 and 23."
+"log.syslog.facility.name":
+  name: "name"
+  flat_name: "log.syslog.facility.name"
+  type: keyword
+  description: "The Syslog text-based facility of the log event, if available."
+"log.syslog.priority":
+  name: "priority"
+  flat_name: "log.syslog.priority"
+  type: long
+  description: "Syslog numeric priority of the event, if available. According to RFCs 5424 and 3164, the priority is 8 * facility + severity. This number is therefore expected to contain a value between 0 and 191."
+"log.syslog.severity.code":
+  name: "code"
+  flat_name: "log.syslog.severity.code"
+  type: long
+  description: "The Syslog numeric severity of the log event, if available. If the event source publishing via Syslog provides a different numeric severity value (e.g. firewall, IDS), your source's numeric severity should go to ` + "`" + `event.severity` + "`" + `. If the event source does not specify a distinct severity, you can optionally copy the Syslog severity to ` + "`" + `event.severity` + "`" + `."
+"log.syslog.severity.name":
+  name: "name"
+  flat_name: "log.syslog.severity.name"
+  type: keyword
+  description: "The Syslog numeric severity of the log event, if available. If the event source publishing via Syslog provides a different severity value (e.g. firewall, IDS), your source's text severity should go to ` + "`" + `log.level` + "`" + `. If the event source does not specify a distinct severity, you can optionally copy the Syslog severity to ` + "`" + `log.level` + "`" + `."
+"message":
+  name: "message"
+  flat_name: "message"
+  type: text
+  description: "For log events the message field contains the log message, optimized for viewing in a log viewer. For structured logs without an original message field, other fields can be concatenated to form a human-readable summary of the event. If multiple messages exist, they can be combined into one message."
+"network.application":
+  name: "application"
+  flat_name: "network.application"
+  type: keyword
+  description: "A name given to an application level protocol. This can be arbitrarily assigned for things like microservices, but also apply to things like skype, icq, facebook, twitter. This would be used in situations where the vendor or service can be decoded such as from the source/dest IP owners, ports, or wire format. The field value must be normalized to lowercase for querying. See the documentation section \"Implementing ECS\"."
+"network.bytes":
+  name: "bytes"
+  flat_name: "network.bytes"
+  type: long
+  description: "Total bytes transferred in both directions. If ` + "`" + `source.bytes` + "`" + ` and ` + "`" + `destination.bytes` + "`" + ` are known, ` + "`" + `network.bytes` + "`" + ` is their sum."
+"network.community_id":
+  name: "community_id"
+  flat_name: "network.community_id"
+  type: keyword
+  description: "A hash of source and destination IPs and ports, as well as the protocol used in a communication. This is a tool-agnostic standard to identify flows. Learn more at https://github.com/corelight/community-id-spec."
+"network.direction":
+  name: "direction"
+  flat_name: "network.direction"
+  type: keyword
+  description: "Direction of the network traffic. Recommended values are:   * inbound * outbound   * internal   * external   * unknown  When mapping events from a host-based monitoring context, populate this field from the host's point of view. When mapping events from a network or perimeter-based monitoring context, populate this field from the point of view of your network perimeter."
+"network.forwarded_ip":
+  name: "forwarded_ip"
+  flat_name: "network.forwarded_ip"
+  type: keyword
+  description: "Host IP address when the source IP address is the proxy."
+"network.iana_number":
+  name: "iana_number"
+  flat_name: "network.iana_number"
+  type: keyword
+  description: "IANA Protocol Number (https://www.iana.org/assignments/protocol-numbers/protocol-numbers.xhtml). Standardized list of protocols. This aligns well with NetFlow and sFlow related logs which use the IANA Protocol Number."
+"network.name":
+  name: "name"
+  flat_name: "network.name"
+  type: keyword
+  description: "Name given by operators to sections of their network."
+"network.packets":
+  name: "packets"
+  flat_name: "network.packets"
+  type: long
+  description: "Total packets transferred in both directions. If ` + "`" + `source.packets` + "`" + ` and ` + "`" + `destination.packets` + "`" + ` are known, ` + "`" + `network.packets` + "`" + ` is their sum."
+"network.protocol":
+  name: "protocol"
+  flat_name: "network.protocol"
+  type: keyword
+  description: "L7 Network protocol name. ex. http, lumberjack, transport protocol. The field value must be normalized to lowercase for querying. See the documentation section \"Implementing ECS\"."
+"network.transport":
+  name: "transport"
+  flat_name: "network.transport"
+  type: keyword
+  description: "Same as network.iana_number, but instead using the Keyword name of the transport layer (udp, tcp, ipv6-icmp, etc.) The field value must be normalized to lowercase for querying. See the documentation section \"Implementing ECS\"."
+"network.type":
+  name: "type"
+  flat_name: "network.type"
+  type: keyword
+  description: "In the OSI Model this would be the Network Layer. ipv4, ipv6, ipsec, pim, etc The field value must be normalized to lowercase for querying. See the documentation section \"Implementing ECS\"."
+"observer.geo.city_name":
+  name: "city_name"
+  flat_name: "observer.geo.city_name"
+  type: keyword
+  description: "City name."
+"observer.geo.continent_name":
+  name: "continent_name"
+  flat_name: "observer.geo.continent_name"
+  type: keyword
+  description: "Name of the continent."
+"observer.geo.country_iso_code":
+  name: "country_iso_code"
+  flat_name: "observer.geo.country_iso_code"
+  type: keyword
+  description: "Country ISO code."
+"observer.geo.country_name":
+  name: "country_name"
+  flat_name: "observer.geo.country_name"
+  type: keyword
+  description: "Country name."
+"observer.geo.location":
+  name: "location"
+  flat_name: "observer.geo.location"
+  type: geo_point
+  description: "Longitude and latitude."
+"observer.geo.name":
+  name: "name"
+  flat_name: "observer.geo.name"
+  type: keyword
+  description: "User-defined description of a location, at the level of granularity they care about. Could be the name of their data centers, the floor number, if this describes a local physical entity, city names. Not typically used in automated geolocation."
+"observer.geo.region_iso_code":
+  name: "region_iso_code"
+  flat_name: "observer.geo.region_iso_code"
+  type: keyword
+  description: "Region ISO code."
+"observer.geo.region_name":
+  name: "region_name"
+  flat_name: "observer.geo.region_name"
+  type: keyword
+  description: "Region name."
+"observer.hostname":
+  name: "hostname"
+  flat_name: "observer.hostname"
+  type: keyword
+  description: "Hostname of the observer."
+"observer.ip":
+  name: "ip"
+  flat_name: "observer.ip"
+  type: ip
+  description: "IP address of the observer."
+"observer.mac":
+  name: "mac"
+  flat_name: "observer.mac"
+  type: keyword
+  description: "MAC address of the observer"
+"observer.name":
+  name: "name"
+  flat_name: "observer.name"
+  type: keyword
+  description: "Custom name of the observer. This is a name that can be given to an observer. This can be helpful for example if multiple firewalls of the same model are used in an organization. If no custom name is needed, the field can be left empty."
+"observer.os.family":
+  name: "family"
+  flat_name: "observer.os.family"
+  type: keyword
+  description: "OS family (such as redhat, debian, freebsd, windows)."
+"observer.os.full":
+  name: "full"
+  flat_name: "observer.os.full"
+  type: keyword
+  description: "Operating system name, including the version or code name."
+"observer.os.kernel":
+  name: "kernel"
+  flat_name: "observer.os.kernel"
+  type: keyword
+  description: "Operating system kernel version as a raw string."
+"observer.os.name":
+  name: "name"
+  flat_name: "observer.os.name"
+  type: keyword
+  description: "Operating system name, without the version."
+"observer.os.platform":
+  name: "platform"
+  flat_name: "observer.os.platform"
+  type: keyword
+  description: "Operating system platform (such centos, ubuntu, windows)."
+"observer.os.version":
+  name: "version"
+  flat_name: "observer.os.version"
+  type: keyword
+  description: "Operating system version as a raw string."
+"observer.product":
+  name: "product"
+  flat_name: "observer.product"
+  type: keyword
+  description: "The product name of the observer."
+"observer.serial_number":
+  name: "serial_number"
+  flat_name: "observer.serial_number"
+  type: keyword
+  description: "Observer serial number."
+"observer.type":
+  name: "type"
+  flat_name: "observer.type"
+  type: keyword
+  description: "The type of the observer the data is coming from. There is no predefined list of observer types. Some examples are ` + "`" + `forwarder` + "`" + `, ` + "`" + `firewall` + "`" + `, ` + "`" + `ids` + "`" + `, ` + "`" + `ips` + "`" + `, ` + "`" + `proxy` + "`" + `, ` + "`" + `poller` + "`" + `, ` + "`" + `sensor` + "`" + `, ` + "`" + `APM server` + "`" + `."
+"observer.vendor":
+  name: "vendor"
+  flat_name: "observer.vendor"
+  type: keyword
+  description: "Vendor name of the observer."
+"observer.version":
+  name: "version"
+  flat_name: "observer.version"
+  type: keyword
+  description: "Observer version."
+"organization.id":
+  name: "id"
+  flat_name: "organization.id"
+  type: keyword
+  description: "Unique identifier for the organization."
+"organization.name":
+  name: "name"
+  flat_name: "organization.name"
+  type: keyword
+  description: "Organization name."
+"os.family":
+  name: "family"
+  flat_name: "os.family"
+  type: keyword
+  description: "OS family (such as redhat, debian, freebsd, windows)."
+"os.full":
+  name: "full"
+  flat_name: "os.full"
+  type: keyword
+  description: "Operating system name, including the version or code name."
+"os.kernel":
+  name: "kernel"
+  flat_name: "os.kernel"
+  type: keyword
+  description: "Operating system kernel version as a raw string."
+"os.name":
+  name: "name"
+  flat_name: "os.name"
+  type: keyword
+  description: "Operating system name, without the version."
+"os.platform":
+  name: "platform"
+  flat_name: "os.platform"
+  type: keyword
+  description: "Operating system platform (such centos, ubuntu, windows)."
+"os.version":
+  name: "version"
+  flat_name: "os.version"
+  type: keyword
+  description: "Operating system version as a raw string."
+"package.architecture":
+  name: "architecture"
+  flat_name: "package.architecture"
+  type: keyword
+  description: "Package architecture."
+"package.build_version":
+  name: "build_version"
+  flat_name: "package.build_version"
+  type: keyword
+  description: "Additional information about the build version of the installed package. For example use the commit SHA of a non-released package."
+"package.checksum":
+  name: "checksum"
+  flat_name: "package.checksum"
+  type: keyword
+  description: "Checksum of the installed package for verification."
+"package.description":
+  name: "description"
+  flat_name: "package.description"
+  type: keyword
+  description: "Description of the package."
+"package.install_scope":
+  name: "install_scope"
+  flat_name: "package.install_scope"
+  type: keyword
+  description: "Indicating how the package was installed, e.g. user-local, global."
+"package.installed":
+  name: "installed"
+  flat_name: "package.installed"
+  type: date
+  description: "Time when package was installed."
+"package.license":
+  name: "license"
+  flat_name: "package.license"
+  type: keyword
+  description: "License under which the package was released. Use a short name, e.g. the license identifier from SPDX License List where possible (https://spdx.org/licenses/)."
+"package.name":
+  name: "name"
+  flat_name: "package.name"
+  type: keyword
+  description: "Package name"
+"package.path":
+  name: "path"
+  flat_name: "package.path"
+  type: keyword
+  description: "Path where the package is installed."
+"package.reference":
+  name: "reference"
+  flat_name: "package.reference"
+  type: keyword
+  description: "Home page or reference URL of the software in this package, if available."
+"package.size":
+  name: "size"
+  flat_name: "package.size"
+  type: long
+  description: "Package size in bytes."
+"package.type":
+  name: "type"
+  flat_name: "package.type"
+  type: keyword
+  description: "Type of package. This should contain the package file type, rather than the package manager name. Examples: rpm, dpkg, brew, npm, gem, nupkg, jar."
+"package.version":
+  name: "version"
+  flat_name: "package.version"
+  type: keyword
+  description: "Package version"
+"process.args":
+  name: "args"
+  flat_name: "process.args"
+  type: keyword
+  description: "Array of process arguments, starting with the absolute path to the executable. May be filtered to protect sensitive information."
+"process.args_count":
+  name: "args_count"
+  flat_name: "process.args_count"
+  type: long
+  description: "Length of the process.args array. This field can be useful for querying or performing bucket analysis on how many arguments were provided to start a process. More arguments may be an indication of suspicious activity."
+"process.command_line":
+  name: "command_line"
+  flat_name: "process.command_line"
+  type: keyword
+  description: "Full command line that started the process, including the absolute path to the executable, and all arguments. Some arguments may be filtered to protect sensitive information."
+"process.executable":
+  name: "executable"
+  flat_name: "process.executable"
+  type: keyword
+  description: "Absolute path to the process executable."
+"process.exit_code":
+  name: "exit_code"
+  flat_name: "process.exit_code"
+  type: long
+  description: "The exit code of the process, if this is a termination event. The field should be absent if there is no exit code for the event (e.g. process start)."
+"process.hash.md5":
+  name: "md5"
+  flat_name: "process.hash.md5"
+  type: keyword
+  description: "MD5 hash."
+"process.hash.sha1":
+  name: "sha1"
+  flat_name: "process.hash.sha1"
+  type: keyword
+  description: "SHA1 hash."
+"process.hash.sha256":
+  name: "sha256"
+  flat_name: "process.hash.sha256"
+  type: keyword
+  description: "SHA256 hash."
+"process.hash.sha512":
+  name: "sha512"
+  flat_name: "process.hash.sha512"
+  type: keyword
+  description: "SHA512 hash."
+"process.name":
+  name: "name"
+  flat_name: "process.name"
+  type: keyword
+  description: "Process name. Sometimes called program name or similar."
+"process.parent.args":
+  name: "args"
+  flat_name: "process.parent.args"
+  type: keyword
+  description: "Array of process arguments. May be filtered to protect sensitive information."
+"process.parent.args_count":
+  name: "args_count"
+  flat_name: "process.parent.args_count"
+  type: long
+  description: "Length of the process.args array. This field can be useful for querying or performing bucket analysis on how many arguments were provided to start a process. More arguments may be an indication of suspicious activity."
+"process.parent.command_line":
+  name: "command_line"
+  flat_name: "process.parent.command_line"
+  type: keyword
+  description: "Full command line that started the process, including the absolute path to the executable, and all arguments. Some arguments may be filtered to protect sensitive information."
+"process.parent.executable":
+  name: "executable"
+  flat_name: "process.parent.executable"
+  type: keyword
+  description: "Absolute path to the process executable."
+"process.parent.exit_code":
+  name: "exit_code"
+  flat_name: "process.parent.exit_code"
+  type: long
+  description: "The exit code of the process, if this is a termination event. The field should be absent if there is no exit code for the event (e.g. process start)."
+"process.parent.name":
+  name: "name"
+  flat_name: "process.parent.name"
+  type: keyword
+  description: "Process name. Sometimes called program name or similar."
+"process.parent.pgid":
+  name: "pgid"
+  flat_name: "process.parent.pgid"
+  type: long
+  description: "Identifier of the group of processes the process belongs to."
+"process.parent.pid":
+  name: "pid"
+  flat_name: "process.parent.pid"
+  type: long
+  description: "Process id."
+"process.parent.ppid":
+  name: "ppid"
+  flat_name: "process.parent.ppid"
+  type: long
+  description: "Parent process' pid."
+"process.parent.start":
+  name: "start"
+  flat_name: "process.parent.start"
+  type: date
+  description: "The time the process started."
+"process.parent.thread.id":
+  name: "id"
+  flat_name: "process.parent.thread.id"
+  type: long
+  description: "Thread ID."
+"process.parent.thread.name":
+  name: "name"
+  flat_name: "process.parent.thread.name"
+  type: keyword
+  description: "Thread name."
+"process.parent.title":
+  name: "title"
+  flat_name: "process.parent.title"
+  type: keyword
+  description: "Process title. The proctitle, some times the same as process name. Can also be different: for example a browser setting its title to the web page currently opened."
+"process.parent.uptime":
+  name: "uptime"
+  flat_name: "process.parent.uptime"
+  type: long
+  description: "Seconds the process has been up."
+"process.parent.working_directory":
+  name: "working_directory"
+  flat_name: "process.parent.working_directory"
+  type: keyword
+  description: "The working directory of the process."
+"process.pgid":
+  name: "pgid"
+  flat_name: "process.pgid"
+  type: long
+  description: "Identifier of the group of processes the process belongs to."
+"process.pid":
+  name: "pid"
+  flat_name: "process.pid"
+  type: long
+  description: "Process id."
+"process.ppid":
+  name: "ppid"
+  flat_name: "process.ppid"
+  type: long
+  description: "Parent process' pid."
+"process.start":
+  name: "start"
+  flat_name: "process.start"
+  type: date
+  description: "The time the process started."
+"process.thread.id":
+  name: "id"
+  flat_name: "process.thread.id"
+  type: long
+  description: "Thread ID."
+"process.thread.name":
+  name: "name"
+  flat_name: "process.thread.name"
+  type: keyword
+  description: "Thread name."
+"process.title":
+  name: "title"
+  flat_name: "process.title"
+  type: keyword
+  description: "Process title. The proctitle, some times the same as process name. Can also be different: for example a browser setting its title to the web page currently opened."
+"process.uptime":
+  name: "uptime"
+  flat_name: "process.uptime"
+  type: long
+  description: "Seconds the process has been up."
+"process.working_directory":
+  name: "working_directory"
+  flat_name: "process.working_directory"
+  type: keyword
+  description: "The working directory of the process."
+"registry.data.bytes":
+  name: "bytes"
+  flat_name: "registry.data.bytes"
+  type: keyword
+  description: "Original bytes written with base64 encoding. For Windows registry operations, such as SetValueEx and RegQueryValueEx, this corresponds to the data pointed by ` + "`" + `lp_data` + "`" + `. This is optional but provides better recoverability and should be populated for REG_BINARY encoded values."
+"registry.data.strings":
+  name: "strings"
+  flat_name: "registry.data.strings"
+  type: keyword
+  description: "Content when writing string types. Populated as an array when writing string data to the registry. For single string registry types (REG_SZ, REG_EXPAND_SZ), this should be an array with one string. For sequences of string with REG_MULTI_SZ, this array will be variable length. For numeric data, such as REG_DWORD and REG_QWORD, this should be populated with the decimal representation (e.g ` + "`" + `\"1\"` + "`" + `)."
+"registry.data.type":
+  name: "type"
+  flat_name: "registry.data.type"
+  type: keyword
+  description: "Standard registry type for encoding contents"
+"registry.hive":
+  name: "hive"
+  flat_name: "registry.hive"
+  type: keyword
+  description: "Abbreviated name for the hive."
+"registry.key":
+  name: "key"
+  flat_name: "registry.key"
+  type: keyword
+  description: "Hive-relative path of keys."
+"registry.path":
+  name: "path"
+  flat_name: "registry.path"
+  type: keyword
+  description: "Full path, including hive, key and value"
+"registry.value":
+  name: "value"
+  flat_name: "registry.value"
+  type: keyword
+  description: "Name of the value written."
+"related.ip":
+  name: "ip"
+  flat_name: "related.ip"
+  type: ip
+  description: "All of the IPs seen on your event."
+"related.user":
+  name: "user"
+  flat_name: "related.user"
+  type: keyword
+  description: "All the user names seen on your event."
+"rule.category":
+  name: "category"
+  flat_name: "rule.category"
+  type: keyword
+  description: "A categorization value keyword used by the entity using the rule for detection of this event."
+"rule.description":
+  name: "description"
+  flat_name: "rule.description"
+  type: keyword
+  description: "The description of the rule generating the event."
+"rule.id":
+  name: "id"
+  flat_name: "rule.id"
+  type: keyword
+  description: "A rule ID that is unique within the scope of an agent, observer, or other entity using the rule for detection of this event."
+"rule.name":
+  name: "name"
+  flat_name: "rule.name"
+  type: keyword
+  description: "The name of the rule or signature generating the event."
+"rule.reference":
+  name: "reference"
+  flat_name: "rule.reference"
+  type: keyword
+  description: "Reference URL to additional information about the rule used to generate this event. The URL can point to the vendor's documentation about the rule. If that's not available, it can also be a link to a more general page describing this type of alert."
+"rule.ruleset":
+  name: "ruleset"
+  flat_name: "rule.ruleset"
+  type: keyword
+  description: "Name of the ruleset, policy, group, or parent category in which the rule used to generate this event is a member."
+"rule.uuid":
+  name: "uuid"
+  flat_name: "rule.uuid"
+  type: keyword
+  description: "A rule ID that is unique within the scope of a set or group of agents, observers, or other entities using the rule for detection of this event."
+"rule.version":
+  name: "version"
+  flat_name: "rule.version"
+  type: keyword
+  description: "The version / revision of the rule being used for analysis."
+"server.address":
+  name: "address"
+  flat_name: "server.address"
+  type: keyword
+  description: "Some event server addresses are defined ambiguously. The event will sometimes list an IP, a domain or a unix socket.  You should always store the raw address in the ` + "`" + `.address` + "`" + ` field. Then it should be duplicated to ` + "`" + `.ip` + "`" + ` or ` + "`" + `.domain` + "`" + `, depending on which one it is."
+"server.as.number":
+  name: "number"
+  flat_name: "server.as.number"
+  type: long
+  description: "Unique number allocated to the autonomous system. The autonomous system number (ASN) uniquely identifies each network on the Internet."
+"server.as.organization.name":
+  name: "name"
+  flat_name: "server.as.organization.name"
+  type: keyword
+  description: "Organization name."
+"server.bytes":
+  name: "bytes"
+  flat_name: "server.bytes"
+  type: long
+  description: "Bytes sent from the server to the client."
+"server.domain":
+  name: "domain"
+  flat_name: "server.domain"
+  type: keyword
+  description: "Server domain."
+"server.geo.city_name":
+  name: "city_name"
+  flat_name: "server.geo.city_name"
+  type: keyword
+  description: "City name."
+"server.geo.continent_name":
+  name: "continent_name"
+  flat_name: "server.geo.continent_name"
+  type: keyword
+  description: "Name of the continent."
+"server.geo.country_iso_code":
+  name: "country_iso_code"
+  flat_name: "server.geo.country_iso_code"
+  type: keyword
+  description: "Country ISO code."
+"server.geo.country_name":
+  name: "country_name"
+  flat_name: "server.geo.country_name"
+  type: keyword
+  description: "Country name."
+"server.geo.location":
+  name: "location"
+  flat_name: "server.geo.location"
+  type: geo_point
+  description: "Longitude and latitude."
+"server.geo.name":
+  name: "name"
+  flat_name: "server.geo.name"
+  type: keyword
+  description: "User-defined description of a location, at the level of granularity they care about. Could be the name of their data centers, the floor number, if this describes a local physical entity, city names. Not typically used in automated geolocation."
+"server.geo.region_iso_code":
+  name: "region_iso_code"
+  flat_name: "server.geo.region_iso_code"
+  type: keyword
+  description: "Region ISO code."
+"server.geo.region_name":
+  name: "region_name"
+  flat_name: "server.geo.region_name"
+  type: keyword
+  description: "Region name."
+"server.ip":
+  name: "ip"
+  flat_name: "server.ip"
+  type: ip
+  description: "IP address of the server. Can be one or multiple IPv4 or IPv6 addresses."
+"server.mac":
+  name: "mac"
+  flat_name: "server.mac"
+  type: keyword
+  description: "MAC address of the server."
+"server.nat.ip":
+  name: "ip"
+  flat_name: "server.nat.ip"
+  type: ip
+  description: "Translated ip of destination based NAT sessions (e.g. internet to private DMZ) Typically used with load balancers, firewalls, or routers."
+"server.nat.port":
+  name: "port"
+  flat_name: "server.nat.port"
+  type: long
+  description: "Translated port of destination based NAT sessions (e.g. internet to private DMZ) Typically used with load balancers, firewalls, or routers."
+"server.packets":
+  name: "packets"
+  flat_name: "server.packets"
+  type: long
+  description: "Packets sent from the server to the client."
+"server.port":
+  name: "port"
+  flat_name: "server.port"
+  type: long
+  description: "Port of the server."
+"server.registered_domain":
+  name: "registered_domain"
+  flat_name: "server.registered_domain"
+  type: keyword
+  description: "The highest registered server domain, stripped of the subdomain. For example, the registered domain for \"foo.google.com\" is \"google.com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last two labels will not work well for TLDs such as \"co.uk\"."
+"server.top_level_domain":
+  name: "top_level_domain"
+  flat_name: "server.top_level_domain"
+  type: keyword
+  description: "The effective top level domain (eTLD), also known as the domain suffix, is the last part of the domain name. For example, the top level domain for google.com is \"com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last label will not work well for effective TLDs such as \"co.uk\"."
+"server.user.domain":
+  name: "domain"
+  flat_name: "server.user.domain"
+  type: keyword
+  description: "Name of the directory the user is a member of. For example, an LDAP or Active Directory domain name."
+"server.user.email":
+  name: "email"
+  flat_name: "server.user.email"
+  type: keyword
+  description: "User email address."
+"server.user.full_name":
+  name: "full_name"
+  flat_name: "server.user.full_name"
+  type: keyword
+  description: "User's full name, if available."
+"server.user.group.domain":
+  name: "domain"
+  flat_name: "server.user.group.domain"
+  type: keyword
+  description: "Name of the directory the group is a member of. For example, an LDAP or Active Directory domain name."
+"server.user.group.id":
+  name: "id"
+  flat_name: "server.user.group.id"
+  type: keyword
+  description: "Unique identifier for the group on the system/platform."
+"server.user.group.name":
+  name: "name"
+  flat_name: "server.user.group.name"
+  type: keyword
+  description: "Name of the group."
+"server.user.hash":
+  name: "hash"
+  flat_name: "server.user.hash"
+  type: keyword
+  description: "Unique user hash to correlate information for a user in anonymized form. Useful if ` + "`" + `user.id` + "`" + ` or ` + "`" + `user.name` + "`" + ` contain confidential information and cannot be used."
+"server.user.id":
+  name: "id"
+  flat_name: "server.user.id"
+  type: keyword
+  description: "One or multiple unique identifiers of the user."
+"server.user.name":
+  name: "name"
+  flat_name: "server.user.name"
+  type: keyword
+  description: "Short name or login of the user."
+"service.ephemeral_id":
+  name: "ephemeral_id"
+  flat_name: "service.ephemeral_id"
+  type: keyword
+  description: "Ephemeral identifier of this service (if one exists). This id normally changes across restarts, but ` + "`" + `service.id` + "`" + ` does not."
+"service.id":
+  name: "id"
+  flat_name: "service.id"
+  type: keyword
+  description: "Unique identifier of the running service. If the service is comprised of many nodes, the ` + "`" + `service.id` + "`" + ` should be the same for all nodes. This id should uniquely identify the service. This makes it possible to correlate logs and metrics for one specific service, no matter which particular node emitted the event. Note that if you need to see the events from one specific host of the service, you should filter on that ` + "`" + `host.name` + "`" + ` or ` + "`" + `host.id` + "`" + ` instead."
+"service.name":
+  name: "name"
+  flat_name: "service.name"
+  type: keyword
+  description: "Name of the service data is collected from. The name of the service is normally user given. This allows for distributed services that run on multiple hosts to correlate the related instances based on the name. In the case of Elasticsearch the ` + "`" + `service.name` + "`" + ` could contain the cluster name. For Beats the ` + "`" + `service.name` + "`" + ` is by default a copy of the ` + "`" + `service.type` + "`" + ` field if no name is specified."
+"service.node.name":
+  name: "name"
+  flat_name: "service.node.name"
+  type: keyword
+  description: "Name of a service node. This allows for two nodes of the same service running on the same host to be differentiated. Therefore, ` + "`" + `service.node.name` + "`" + ` should typically be unique across nodes of a given service. In the case of Elasticsearch, the ` + "`" + `service.node.name` + "`" + ` could contain the unique node name within the Elasticsearch cluster. In cases where the service doesn't have the concept of a node name, the host name or container name can be used to distinguish running instances that make up this service. If those do not provide uniqueness (e.g. multiple instances of the service running on the same host) - the node name can be manually set."
+"service.state":
+  name: "state"
+  flat_name: "service.state"
+  type: keyword
+  description: "Current state of the service."
+"service.type":
+  name: "type"
+  flat_name: "service.type"
+  type: keyword
+  description: "The type of the service data is collected from. The type can be used to group and correlate logs and metrics from one service type. Example: If logs or metrics are collected from Elasticsearch, ` + "`" + `service.type` + "`" + ` would be ` + "`" + `elasticsearch` + "`" + `."
+"service.version":
+  name: "version"
+  flat_name: "service.version"
+  type: keyword
+  description: "Version of the service the data was collected from. This allows to look at a data set only for a specific version of a service."
+"source.address":
+  name: "address"
+  flat_name: "source.address"
+  type: keyword
+  description: "Some event source addresses are defined ambiguously. The event will sometimes list an IP, a domain or a unix socket.  You should always store the raw address in the ` + "`" + `.address` + "`" + ` field. Then it should be duplicated to ` + "`" + `.ip` + "`" + ` or ` + "`" + `.domain` + "`" + `, depending on which one it is."
+"source.as.number":
+  name: "number"
+  flat_name: "source.as.number"
+  type: long
+  description: "Unique number allocated to the autonomous system. The autonomous system number (ASN) uniquely identifies each network on the Internet."
+"source.as.organization.name":
+  name: "name"
+  flat_name: "source.as.organization.name"
+  type: keyword
+  description: "Organization name."
+"source.bytes":
+  name: "bytes"
+  flat_name: "source.bytes"
+  type: long
+  description: "Bytes sent from the source to the destination."
+"source.domain":
+  name: "domain"
+  flat_name: "source.domain"
+  type: keyword
+  description: "Source domain."
+"source.geo.city_name":
+  name: "city_name"
+  flat_name: "source.geo.city_name"
+  type: keyword
+  description: "City name."
+"source.geo.continent_name":
+  name: "continent_name"
+  flat_name: "source.geo.continent_name"
+  type: keyword
+  description: "Name of the continent."
+"source.geo.country_iso_code":
+  name: "country_iso_code"
+  flat_name: "source.geo.country_iso_code"
+  type: keyword
+  description: "Country ISO code."
+"source.geo.country_name":
+  name: "country_name"
+  flat_name: "source.geo.country_name"
+  type: keyword
+  description: "Country name."
+"source.geo.location":
+  name: "location"
+  flat_name: "source.geo.location"
+  type: geo_point
+  description: "Longitude and latitude."
+"source.geo.name":
+  name: "name"
+  flat_name: "source.geo.name"
+  type: keyword
+  description: "User-defined description of a location, at the level of granularity they care about. Could be the name of their data centers, the floor number, if this describes a local physical entity, city names. Not typically used in automated geolocation."
+"source.geo.region_iso_code":
+  name: "region_iso_code"
+  flat_name: "source.geo.region_iso_code"
+  type: keyword
+  description: "Region ISO code."
+"source.geo.region_name":
+  name: "region_name"
+  flat_name: "source.geo.region_name"
+  type: keyword
+  description: "Region name."
+"source.ip":
+  name: "ip"
+  flat_name: "source.ip"
+  type: ip
+  description: "IP address of the source. Can be one or multiple IPv4 or IPv6 addresses."
+"source.mac":
+  name: "mac"
+  flat_name: "source.mac"
+  type: keyword
+  description: "MAC address of the source."
+"source.nat.ip":
+  name: "ip"
+  flat_name: "source.nat.ip"
+  type: ip
+  description: "Translated ip of source based NAT sessions (e.g. internal client to internet) Typically connections traversing load balancers, firewalls, or routers."
+"source.nat.port":
+  name: "port"
+  flat_name: "source.nat.port"
+  type: long
+  description: "Translated port of source based NAT sessions. (e.g. internal client to internet) Typically used with load balancers, firewalls, or routers."
+"source.packets":
+  name: "packets"
+  flat_name: "source.packets"
+  type: long
+  description: "Packets sent from the source to the destination."
+"source.port":
+  name: "port"
+  flat_name: "source.port"
+  type: long
+  description: "Port of the source."
+"source.registered_domain":
+  name: "registered_domain"
+  flat_name: "source.registered_domain"
+  type: keyword
+  description: "The highest registered source domain, stripped of the subdomain. For example, the registered domain for \"foo.google.com\" is \"google.com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last two labels will not work well for TLDs such as \"co.uk\"."
+"source.top_level_domain":
+  name: "top_level_domain"
+  flat_name: "source.top_level_domain"
+  type: keyword
+  description: "The effective top level domain (eTLD), also known as the domain suffix, is the last part of the domain name. For example, the top level domain for google.com is \"com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last label will not work well for effective TLDs such as \"co.uk\"."
+"source.user.domain":
+  name: "domain"
+  flat_name: "source.user.domain"
+  type: keyword
+  description: "Name of the directory the user is a member of. For example, an LDAP or Active Directory domain name."
+"source.user.email":
+  name: "email"
+  flat_name: "source.user.email"
+  type: keyword
+  description: "User email address."
+"source.user.full_name":
+  name: "full_name"
+  flat_name: "source.user.full_name"
+  type: keyword
+  description: "User's full name, if available."
+"source.user.group.domain":
+  name: "domain"
+  flat_name: "source.user.group.domain"
+  type: keyword
+  description: "Name of the directory the group is a member of. For example, an LDAP or Active Directory domain name."
+"source.user.group.id":
+  name: "id"
+  flat_name: "source.user.group.id"
+  type: keyword
+  description: "Unique identifier for the group on the system/platform."
+"source.user.group.name":
+  name: "name"
+  flat_name: "source.user.group.name"
+  type: keyword
+  description: "Name of the group."
+"source.user.hash":
+  name: "hash"
+  flat_name: "source.user.hash"
+  type: keyword
+  description: "Unique user hash to correlate information for a user in anonymized form. Useful if ` + "`" + `user.id` + "`" + ` or ` + "`" + `user.name` + "`" + ` contain confidential information and cannot be used."
+"source.user.id":
+  name: "id"
+  flat_name: "source.user.id"
+  type: keyword
+  description: "One or multiple unique identifiers of the user."
+"source.user.name":
+  name: "name"
+  flat_name: "source.user.name"
+  type: keyword
+  description: "Short name or login of the user."
+"tags":
+  name: "tags"
+  flat_name: "tags"
+  type: keyword
+  description: "List of keywords used to tag each event."
+"threat.framework":
+  name: "framework"
+  flat_name: "threat.framework"
+  type: keyword
+  description: "Name of the threat framework used to further categorize and classify the tactic and technique of the reported threat. Framework classification can be provided by detecting systems, evaluated at ingest time, or retrospectively tagged to events."
+"threat.tactic.id":
+  name: "id"
+  flat_name: "threat.tactic.id"
+  type: keyword
+  description: "The id of tactic used by this threat. You can use the Mitre ATT&CK Matrix Tactic categorization, for example. (ex. https://attack.mitre.org/tactics/TA0040/ )"
+"threat.tactic.name":
+  name: "name"
+  flat_name: "threat.tactic.name"
+  type: keyword
+  description: "Name of the type of tactic used by this threat. You can use the Mitre ATT&CK Matrix Tactic categorization, for example. (ex. https://attack.mitre.org/tactics/TA0040/ )"
+"threat.tactic.reference":
+  name: "reference"
+  flat_name: "threat.tactic.reference"
+  type: keyword
+  description: "The reference url of tactic used by this threat. You can use the Mitre ATT&CK Matrix Tactic categorization, for example. (ex. https://attack.mitre.org/tactics/TA0040/ )"
+"threat.technique.id":
+  name: "id"
+  flat_name: "threat.technique.id"
+  type: keyword
+  description: "The id of technique used by this tactic. You can use the Mitre ATT&CK Matrix Tactic categorization, for example. (ex. https://attack.mitre.org/techniques/T1499/ )"
+"threat.technique.name":
+  name: "name"
+  flat_name: "threat.technique.name"
+  type: keyword
+  description: "The name of technique used by this tactic. You can use the Mitre ATT&CK Matrix Tactic categorization, for example. (ex. https://attack.mitre.org/techniques/T1499/ )"
+"threat.technique.reference":
+  name: "reference"
+  flat_name: "threat.technique.reference"
+  type: keyword
+  description: "The reference url of technique used by this tactic. You can use the Mitre ATT&CK Matrix Tactic categorization, for example. (ex. https://attack.mitre.org/techniques/T1499/ )"
+"tls.cipher":
+  name: "cipher"
+  flat_name: "tls.cipher"
+  type: keyword
+  description: "String indicating the cipher used during the current connection."
+"tls.client.certificate":
+  name: "certificate"
+  flat_name: "tls.client.certificate"
+  type: keyword
+  description: "PEM-encoded stand-alone certificate offered by the client. This is usually mutually-exclusive of ` + "`" + `client.certificate_chain` + "`" + ` since this value also exists in that list."
+"tls.client.certificate_chain":
+  name: "certificate_chain"
+  flat_name: "tls.client.certificate_chain"
+  type: keyword
+  description: "Array of PEM-encoded certificates that make up the certificate chain offered by the client. This is usually mutually-exclusive of ` + "`" + `client.certificate` + "`" + ` since that value should be the first certificate in the chain."
+"tls.client.hash.md5":
+  name: "md5"
+  flat_name: "tls.client.hash.md5"
+  type: keyword
+  description: "Certificate fingerprint using the MD5 digest of DER-encoded version of certificate offered by the client. For consistency with other hash values, this value should be formatted as an uppercase hash."
+"tls.client.hash.sha1":
+  name: "sha1"
+  flat_name: "tls.client.hash.sha1"
+  type: keyword
+  description: "Certificate fingerprint using the SHA1 digest of DER-encoded version of certificate offered by the client. For consistency with other hash values, this value should be formatted as an uppercase hash."
+"tls.client.hash.sha256":
+  name: "sha256"
+  flat_name: "tls.client.hash.sha256"
+  type: keyword
+  description: "Certificate fingerprint using the SHA256 digest of DER-encoded version of certificate offered by the client. For consistency with other hash values, this value should be formatted as an uppercase hash."
+"tls.client.issuer":
+  name: "issuer"
+  flat_name: "tls.client.issuer"
+  type: keyword
+  description: "Distinguished name of subject of the issuer of the x.509 certificate presented by the client."
+"tls.client.ja3":
+  name: "ja3"
+  flat_name: "tls.client.ja3"
+  type: keyword
+  description: "A hash that identifies clients based on how they perform an SSL/TLS handshake."
+"tls.client.not_after":
+  name: "not_after"
+  flat_name: "tls.client.not_after"
+  type: date
+  description: "Date/Time indicating when client certificate is no longer considered valid."
+"tls.client.not_before":
+  name: "not_before"
+  flat_name: "tls.client.not_before"
+  type: date
+  description: "Date/Time indicating when client certificate is first considered valid."
+"tls.client.server_name":
+  name: "server_name"
+  flat_name: "tls.client.server_name"
+  type: keyword
+  description: "Also called an SNI, this tells the server which hostname to which the client is attempting to connect. When this value is available, it should get copied to ` + "`" + `destination.domain` + "`" + `."
+"tls.client.subject":
+  name: "subject"
+  flat_name: "tls.client.subject"
+  type: keyword
+  description: "Distinguished name of subject of the x.509 certificate presented by the client."
+"tls.client.supported_ciphers":
+  name: "supported_ciphers"
+  flat_name: "tls.client.supported_ciphers"
+  type: keyword
+  description: "Array of ciphers offered by the client during the client hello."
+"tls.curve":
+  name: "curve"
+  flat_name: "tls.curve"
+  type: keyword
+  description: "String indicating the curve used for the given cipher, when applicable."
+"tls.established":
+  name: "established"
+  flat_name: "tls.established"
+  type: boolean
+  description: "Boolean flag indicating if the TLS negotiation was successful and transitioned to an encrypted tunnel."
+"tls.next_protocol":
+  name: "next_protocol"
+  flat_name: "tls.next_protocol"
+  type: keyword
+  description: "String indicating the protocol being tunneled. Per the values in the IANA registry (https://www.iana.org/assignments/tls-extensiontype-values/tls-extensiontype-values.xhtml#alpn-protocol-ids), this string should be lower case."
+"tls.resumed":
+  name: "resumed"
+  flat_name: "tls.resumed"
+  type: boolean
+  description: "Boolean flag indicating if this TLS connection was resumed from an existing TLS negotiation."
+"tls.server.certificate":
+  name: "certificate"
+  flat_name: "tls.server.certificate"
+  type: keyword
+  description: "PEM-encoded stand-alone certificate offered by the server. This is usually mutually-exclusive of ` + "`" + `server.certificate_chain` + "`" + ` since this value also exists in that list."
+"tls.server.certificate_chain":
+  name: "certificate_chain"
+  flat_name: "tls.server.certificate_chain"
+  type: keyword
+  description: "Array of PEM-encoded certificates that make up the certificate chain offered by the server. This is usually mutually-exclusive of ` + "`" + `server.certificate` + "`" + ` since that value should be the first certificate in the chain."
+"tls.server.hash.md5":
+  name: "md5"
+  flat_name: "tls.server.hash.md5"
+  type: keyword
+  description: "Certificate fingerprint using the MD5 digest of DER-encoded version of certificate offered by the server. For consistency with other hash values, this value should be formatted as an uppercase hash."
+"tls.server.hash.sha1":
+  name: "sha1"
+  flat_name: "tls.server.hash.sha1"
+  type: keyword
+  description: "Certificate fingerprint using the SHA1 digest of DER-encoded version of certificate offered by the server. For consistency with other hash values, this value should be formatted as an uppercase hash."
+"tls.server.hash.sha256":
+  name: "sha256"
+  flat_name: "tls.server.hash.sha256"
+  type: keyword
+  description: "Certificate fingerprint using the SHA256 digest of DER-encoded version of certificate offered by the server. For consistency with other hash values, this value should be formatted as an uppercase hash."
+"tls.server.issuer":
+  name: "issuer"
+  flat_name: "tls.server.issuer"
+  type: keyword
+  description: "Subject of the issuer of the x.509 certificate presented by the server."
+"tls.server.ja3s":
+  name: "ja3s"
+  flat_name: "tls.server.ja3s"
+  type: keyword
+  description: "A hash that identifies servers based on how they perform an SSL/TLS handshake."
+"tls.server.not_after":
+  name: "not_after"
+  flat_name: "tls.server.not_after"
+  type: date
+  description: "Timestamp indicating when server certificate is no longer considered valid."
+"tls.server.not_before":
+  name: "not_before"
+  flat_name: "tls.server.not_before"
+  type: date
+  description: "Timestamp indicating when server certificate is first considered valid."
+"tls.server.subject":
+  name: "subject"
+  flat_name: "tls.server.subject"
+  type: keyword
+  description: "Subject of the x.509 certificate presented by the server."
+"tls.version":
+  name: "version"
+  flat_name: "tls.version"
+  type: keyword
+  description: "Numeric part of the version parsed from the original string."
+"tls.version_protocol":
+  name: "version_protocol"
+  flat_name: "tls.version_protocol"
+  type: keyword
+  description: "Normalized lowercase protocol name parsed from original string."
+"trace.id":
+  name: "id"
+  flat_name: "trace.id"
+  type: keyword
+  description: "Unique identifier of the trace. A trace groups multiple events like transactions that belong together. For example, a user request handled by multiple inter-connected services."
+"transaction.id":
+  name: "id"
+  flat_name: "transaction.id"
+  type: keyword
+  description: "Unique identifier of the transaction. A transaction is the highest level of work measured within a service, such as a request to a server."
+"url.domain":
+  name: "domain"
+  flat_name: "url.domain"
+  type: keyword
+  description: "Domain of the url, such as \"www.elastic.co\". In some cases a URL may refer to an IP and/or port directly, without a domain name. In this case, the IP address would go to the ` + "`" + `domain` + "`" + ` field."
+"url.extension":
+  name: "extension"
+  flat_name: "url.extension"
+  type: keyword
+  description: "The field contains the file extension from the original request url. The file extension is only set if it exists, as not every url has a file extension. The leading period must not be included. For example, the value must be \"png\", not \".png\"."
+"url.fragment":
+  name: "fragment"
+  flat_name: "url.fragment"
+  type: keyword
+  description: "Portion of the url after the ` + "`" + `#` + "`" + `, such as \"top\". The ` + "`" + `#` + "`" + ` is not part of the fragment."
+"url.full":
+  name: "full"
+  flat_name: "url.full"
+  type: keyword
+  description: "If full URLs are important to your use case, they should be stored in ` + "`" + `url.full` + "`" + `, whether this field is reconstructed or present in the event source."
+"url.original":
+  name: "original"
+  flat_name: "url.original"
+  type: keyword
+  description: "Unmodified original url as seen in the event source. Note that in network monitoring, the observed URL may be a full URL, whereas in access logs, the URL is often just represented as a path. This field is meant to represent the URL as it was observed, complete or not."
+"url.password":
+  name: "password"
+  flat_name: "url.password"
+  type: keyword
+  description: "Password of the request."
+"url.path":
+  name: "path"
+  flat_name: "url.path"
+  type: keyword
+  description: "Path of the request, such as \"/search\"."
+"url.port":
+  name: "port"
+  flat_name: "url.port"
+  type: long
+  description: "Port of the request, such as 443."
+"url.query":
+  name: "query"
+  flat_name: "url.query"
+  type: keyword
+  description: "The query field describes the query string of the request, such as \"q=elasticsearch\". The ` + "`" + `?` + "`" + ` is excluded from the query string. If a URL contains no ` + "`" + `?` + "`" + `, there is no query field. If there is a ` + "`" + `?` + "`" + ` but no query, the query field exists with an empty string. The ` + "`" + `exists` + "`" + ` query can be used to differentiate between the two cases."
+"url.registered_domain":
+  name: "registered_domain"
+  flat_name: "url.registered_domain"
+  type: keyword
+  description: "The highest registered url domain, stripped of the subdomain. For example, the registered domain for \"foo.google.com\" is \"google.com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last two labels will not work well for TLDs such as \"co.uk\"."
+"url.scheme":
+  name: "scheme"
+  flat_name: "url.scheme"
+  type: keyword
+  description: "Scheme of the request, such as \"https\". Note: The ` + "`" + `:` + "`" + ` is not part of the scheme."
+"url.top_level_domain":
+  name: "top_level_domain"
+  flat_name: "url.top_level_domain"
+  type: keyword
+  description: "The effective top level domain (eTLD), also known as the domain suffix, is the last part of the domain name. For example, the top level domain for google.com is \"com\". This value can be determined precisely with a list like the public suffix list (http://publicsuffix.org). Trying to approximate this by simply taking the last label will not work well for effective TLDs such as \"co.uk\"."
+"url.username":
+  name: "username"
+  flat_name: "url.username"
+  type: keyword
+  description: "Username of the request."
+"user.domain":
+  name: "domain"
+  flat_name: "user.domain"
+  type: keyword
+  description: "Name of the directory the user is a member of. For example, an LDAP or Active Directory domain name."
+"user.email":
+  name: "email"
+  flat_name: "user.email"
+  type: keyword
+  description: "User email address."
+"user.full_name":
+  name: "full_name"
+  flat_name: "user.full_name"
+  type: keyword
+  description: "User's full name, if available."
+"user.group.domain":
+  name: "domain"
+  flat_name: "user.group.domain"
+  type: keyword
+  description: "Name of the directory the group is a member of. For example, an LDAP or Active Directory domain name."
+"user.group.id":
+  name: "id"
+  flat_name: "user.group.id"
+  type: keyword
+  description: "Unique identifier for the group on the system/platform."
+"user.group.name":
+  name: "name"
+  flat_name: "user.group.name"
+  type: keyword
+  description: "Name of the group."
+"user.hash":
+  name: "hash"
+  flat_name: "user.hash"
+  type: keyword
+  description: "Unique user hash to correlate information for a user in anonymized form. Useful if ` + "`" + `user.id` + "`" + ` or ` + "`" + `user.name` + "`" + ` contain confidential information and cannot be used."
+"user.id":
+  name: "id"
+  flat_name: "user.id"
+  type: keyword
+  description: "One or multiple unique identifiers of the user."
+"user.name":
+  name: "name"
+  flat_name: "user.name"
+  type: keyword
+  description: "Short name or login of the user."
+"user_agent.device.name":
+  name: "name"
+  flat_name: "user_agent.device.name"
+  type: keyword
+  description: "Name of the device."
+"user_agent.name":
+  name: "name"
+  flat_name: "user_agent.name"
+  type: keyword
+  description: "Name of the user agent."
+"user_agent.original":
+  name: "original"
+  flat_name: "user_agent.original"
+  type: keyword
+  description: "Unparsed user_agent string."
+"user_agent.os.family":
+  name: "family"
+  flat_name: "user_agent.os.family"
+  type: keyword
+  description: "OS family (such as redhat, debian, freebsd, windows)."
+"user_agent.os.full":
+  name: "full"
+  flat_name: "user_agent.os.full"
+  type: keyword
+  description: "Operating system name, including the version or code name."
+"user_agent.os.kernel":
+  name: "kernel"
+  flat_name: "user_agent.os.kernel"
+  type: keyword
+  description: "Operating system kernel version as a raw string."
+"user_agent.os.name":
+  name: "name"
+  flat_name: "user_agent.os.name"
+  type: keyword
+  description: "Operating system name, without the version."
+"user_agent.os.platform":
+  name: "platform"
+  flat_name: "user_agent.os.platform"
+  type: keyword
+  description: "Operating system platform (such centos, ubuntu, windows)."
+"user_agent.os.version":
+  name: "version"
+  flat_name: "user_agent.os.version"
+  type: keyword
+  description: "Operating system version as a raw string."
+"user_agent.version":
+  name: "version"
+  flat_name: "user_agent.version"
+  type: keyword
+  description: "Version of the user agent."
+"vulnerability.category":
+  name: "category"
+  flat_name: "vulnerability.category"
+  type: keyword
+  description: "The type of system or architecture that the vulnerability affects. These may be platform-specific (for example, Debian or SUSE) or general (for example, Database or Firewall). For example (https://qualysguard.qualys.com/qwebhelp/fo_portal/knowledgebase/vulnerability_categories.htm[Qualys vulnerability categories]) This field must be an array."
+"vulnerability.classification":
+  name: "classification"
+  flat_name: "vulnerability.classification"
+  type: keyword
+  description: "The classification of the vulnerability scoring system. For example (https://www.first.org/cvss/)"
+"vulnerability.description":
+  name: "description"
+  flat_name: "vulnerability.description"
+  type: keyword
+  description: "The description of the vulnerability that provides additional context of the vulnerability. For example (https://cve.mitre.org/about/faqs.html#cve_entry_descriptions_created[Common Vulnerabilities and Exposure CVE description])"
+"vulnerability.enumeration":
+  name: "enumeration"
+  flat_name: "vulnerability.enumeration"
+  type: keyword
+  description: "The type of identifier used for this vulnerability. For example (https://cve.mitre.org/about/)"
+"vulnerability.id":
+  name: "id"
+  flat_name: "vulnerability.id"
+  type: keyword
+  description: "The identification (ID) is the number portion of a vulnerability entry. It includes a unique identification number for the vulnerability. For example (https://cve.mitre.org/about/faqs.html#what_is_cve_id)[Common Vulnerabilities and Exposure CVE ID]"
+"vulnerability.reference":
+  name: "reference"
+  flat_name: "vulnerability.reference"
+  type: keyword
+  description: "A resource that provides additional information, context, and mitigations for the identified vulnerability."
+"vulnerability.report_id":
+  name: "report_id"
+  flat_name: "vulnerability.report_id"
+  type: keyword
+  description: "The report or scan identification number."
+"vulnerability.scanner.vendor":
+  name: "vendor"
+  flat_name: "vulnerability.scanner.vendor"
+  type: keyword
+  description: "The name of the vulnerability scanner vendor."
+"vulnerability.score.base":
+  name: "base"
+  flat_name: "vulnerability.score.base"
+  type: float
+  description: "Scores can range from 0.0 to 10.0, with 10.0 being the most severe. Base scores cover an assessment for exploitability metrics (attack vector, complexity, privileges, and user interaction), impact metrics (confidentiality, integrity, and availability), and scope. For example (https://www.first.org/cvss/specification-document)"
+"vulnerability.score.environmental":
+  name: "environmental"
+  flat_name: "vulnerability.score.environmental"
+  type: float
+  description: "Scores can range from 0.0 to 10.0, with 10.0 being the most severe. Environmental scores cover an assessment for any modified Base metrics, confidentiality, integrity, and availability requirements. For example (https://www.first.org/cvss/specification-document)"
+"vulnerability.score.temporal":
+  name: "temporal"
+  flat_name: "vulnerability.score.temporal"
+  type: float
+  description: "Scores can range from 0.0 to 10.0, with 10.0 being the most severe. Temporal scores cover an assessment for code maturity, remediation level, and confidence. For example (https://www.first.org/cvss/specification-document)"
+"vulnerability.score.version":
+  name: "version"
+  flat_name: "vulnerability.score.version"
+  type: keyword
+  description: "The National Vulnerability Database (NVD) provides qualitative severity rankings of \"Low\", \"Medium\", and \"High\" for CVSS v2.0 base score ranges in addition to the severity ratings for CVSS v3.0 as they are defined in the CVSS v3.0 specification. CVSS is owned and managed by FIRST.Org, Inc. (FIRST), a US-based non-profit organization, whose mission is to help computer security incident response teams across the world. For example (https://nvd.nist.gov/vuln-metrics/cvss)"
+"vulnerability.severity":
+  name: "severity"
+  flat_name: "vulnerability.severity"
+  type: keyword
+  description: "The severity of the vulnerability can help with metrics and internal prioritization regarding remediation. For example (https://nvd.nist.gov/vuln-metrics/cvss)"
+`
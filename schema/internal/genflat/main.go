@@ -0,0 +1,228 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+// genflat generates the ECS schema embedded in package schema from the field
+// constructors of github.com/urso/diag-ecs/ecs.
+//
+// Usage:
+//
+//	go run ./internal/genflat -out ecs_flat.go
+//
+// The ecs package does not ship the ECS definitions, so the field types are
+// derived from the Go types of the field constructors. ECS types that map to
+// the same Go type, like `text` or `ip` for strings, are listed explicitly in
+// typeOverrides and must be kept in sync with the ECS version of the ecs
+// package. Replace the generated schema by loading the ECS
+// generated/ecs/ecs_flat.yml file via schema.Load, if exact definitions
+// (multi-fields, index settings) are required.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const ecsPackage = "github.com/urso/diag-ecs/ecs"
+
+type field struct {
+	name, typ, desc string
+}
+
+// goTypes maps the field constructors of the ecs package to ECS types.
+var goTypes = map[string]string{
+	"ecsString":  "keyword",
+	"ecsBool":    "boolean",
+	"ecsInt":     "integer",
+	"ecsInt64":   "long",
+	"ecsFloat64": "float",
+	"ecsTime":    "date",
+}
+
+// typeOverrides lists the fields of ECS 1.4 with a type that can not be
+// derived from the Go type of the field constructor.
+var typeOverrides = map[string]string{
+	"message":       "text",
+	"error.message": "text",
+
+	"client.ip":          "ip",
+	"client.nat.ip":      "ip",
+	"destination.ip":     "ip",
+	"destination.nat.ip": "ip",
+	"host.ip":            "ip",
+	"observer.ip":        "ip",
+	"related.ip":         "ip",
+	"server.ip":          "ip",
+	"server.nat.ip":      "ip",
+	"source.ip":          "ip",
+	"source.nat.ip":      "ip",
+
+	"client.geo.location":      "geo_point",
+	"destination.geo.location": "geo_point",
+	"geo.location":             "geo_point",
+	"host.geo.location":        "geo_point",
+	"observer.geo.location":    "geo_point",
+	"server.geo.location":      "geo_point",
+	"source.geo.location":      "geo_point",
+}
+
+// extraFields lists the fields of ECS 1.4 without a field constructor in the
+// ecs package.
+var extraFields = []field{
+	{"labels", "object", "Custom key/value pairs. Can be used to add meta information to events. Should not contain nested objects. All values are stored as keyword."},
+	{"tags", "keyword", "List of keywords used to tag each event."},
+	{"container.labels", "object", "Image labels."},
+}
+
+func main() {
+	out := flag.String("out", "ecs_flat.go", "output file")
+	src := flag.String("src", "", "source directory of the ecs package (default: resolved via go list)")
+	flag.Parse()
+
+	dir := *src
+	if dir == "" {
+		var err error
+		if dir, err = packageDir(ecsPackage); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	fields, err := parseFields(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	content, err := generate(fields)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, content, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func packageDir(pkg string) (string, error) {
+	out, err := exec.Command("go", "list", "-f", "{{.Dir}}", pkg).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate package %v: %v", pkg, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// parseFields collects the fields from the field constructors, like:
+//
+//	// Hostname create the ECS complain 'host.hostname' field.
+//	// <description>
+//	func (nsHost) Hostname(value string) diag.Field {
+//		return ecsString("host.hostname", value)
+//	}
+func parseFields(dir string) ([]field, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	pkg, ok := pkgs["ecs"]
+	if !ok {
+		return nil, fmt.Errorf("no ecs package found in %v", dir)
+	}
+
+	var fields []field
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || len(fn.Body.List) != 1 {
+				continue
+			}
+			ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				continue
+			}
+			call, ok := ret.Results[0].(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				continue
+			}
+			ident, ok := call.Fun.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			typ, ok := goTypes[ident.Name]
+			if !ok {
+				continue
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				continue
+			}
+
+			name, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return nil, err
+			}
+			if override, exists := typeOverrides[name]; exists {
+				typ = override
+			}
+
+			var desc string
+			if fn.Doc != nil {
+				// skip the first line naming the constructor
+				lines := strings.Split(strings.TrimSpace(fn.Doc.Text()), "\n")
+				desc = strings.Join(lines[1:], " ")
+			}
+			fields = append(fields, field{name: name, typ: typ, desc: desc})
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no field constructors found in %v", dir)
+	}
+
+	fields = append(fields, extraFields...)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+	return fields, nil
+}
+
+func generate(fields []field) ([]byte, error) {
+	var yml strings.Builder
+	for _, f := range fields {
+		fmt.Fprintf(&yml, "%q:\n", f.name)
+		fmt.Fprintf(&yml, "  name: %q\n", f.name[strings.LastIndexByte(f.name, '.')+1:])
+		fmt.Fprintf(&yml, "  flat_name: %q\n", f.name)
+		fmt.Fprintf(&yml, "  type: %v\n", f.typ)
+		if f.desc != "" {
+			fmt.Fprintf(&yml, "  description: %v\n", strconv.Quote(f.desc))
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	fmt.Fprintf(&buf, "// Code generated by genflat from %v. DO NOT EDIT.\n\n", ecsPackage)
+	buf.WriteString("package schema\n\n")
+	buf.WriteString("// ecsFlatYML contains the ECS field definitions in the format of ECS\n")
+	buf.WriteString("// generated/ecs/ecs_flat.yml.\n")
+	buf.WriteString("const ecsFlatYML = `\n")
+	buf.WriteString(strings.Replace(yml.String(), "`", "` + \"`\" + `", -1))
+	buf.WriteString("`\n")
+	return format.Source(buf.Bytes())
+}
+
+const header = `// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+`
@@ -0,0 +1,281 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+// Package schema loads ECS compatible field definitions.
+//
+// Definitions are read from yaml files in one of two formats:
+//
+// The map format is used by ECS for generated/ecs/ecs_flat.yml and
+// generated/ecs/ecs_nested.yml. Fields are indexed by their name.
+//
+// The list format is used by the ECS source files in schemas/*.yml and by
+// the fields.yml files of Beats. Each document is a list of field
+// definitions, with nested fields being listed under `fields`. Sections
+// with a `key`, like in fields.yml, and groups marked as `root` define
+// their fields at the root of the document. Fields without a type are of
+// type keyword.
+//
+// Fields in the `base` group are defined at the root of the document.
+package schema
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/urso/diag-ecs/ecs"
+	yaml "gopkg.in/yaml.v2"
+)
+
+//go:generate go run ./internal/genflat -out ecs_flat.go
+
+// Schema is a set of field definitions, indexed by the fully qualified field
+// name.
+type Schema struct {
+	Version    string
+	fields     map[string]*Field
+	namespaces map[string]bool
+	groups     map[string]string // descriptions of field groups
+}
+
+// Field is the definition of a single field.
+type Field struct {
+	Name        string // fully qualified name, like `host.hostname`
+	Type        string // Elasticsearch field type, like `keyword` or `long`
+	Description string
+}
+
+// definition represents a field specification in a yaml file.
+type definition struct {
+	Name        string
+	FlatName    string `yaml:"flat_name"`
+	Type        string
+	Description string
+	Fields      map[string]definition
+}
+
+// listDefinition represents a field specification in a yaml file using the
+// list format.
+type listDefinition struct {
+	Key         string // section in fields.yml, fields are defined at the root
+	Name        string
+	Type        string
+	Description string
+	Root        bool // the fields of the group are defined at the root
+	Fields      []listDefinition
+}
+
+var ecsSchema struct {
+	once   sync.Once
+	schema *Schema
+}
+
+// ECS returns the schema of the ECS version supported by the ecs package.
+// The schema is generated from the field constructors of the ecs package.
+// It defines the fields and types, but no multi-fields or index settings.
+// Use Load with the ECS generated/ecs/ecs_flat.yml file for the complete
+// definitions.
+func ECS() *Schema {
+	ecsSchema.once.Do(func() {
+		s, err := Parse(ecs.Version, []byte(ecsFlatYML))
+		if err != nil {
+			panic(fmt.Sprintf("invalid embedded ECS schema: %v", err))
+		}
+		ecsSchema.schema = s
+	})
+	return ecsSchema.schema
+}
+
+// Load reads the field definitions from the given paths. All `*.yml` files
+// are read if a path is a directory. Definitions in later files overwrite
+// definitions of the same field in earlier files.
+func Load(version string, paths ...string) (*Schema, error) {
+	var contents [][]byte
+	for _, path := range paths {
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		files := []string{path}
+		if stat.IsDir() {
+			files, err = filepath.Glob(filepath.Join(path, "*.yml"))
+			if err != nil {
+				return nil, fmt.Errorf("finding yml files in '%v' failed: %v", path, err)
+			}
+		}
+
+		for _, file := range files {
+			c, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("error reading file %v: %v", file, err)
+			}
+			contents = append(contents, c)
+		}
+	}
+
+	return Parse(version, contents...)
+}
+
+// Parse creates a schema from the contents of one or more yaml files. The
+// format of each file is detected from its contents.
+func Parse(version string, contents ...[]byte) (*Schema, error) {
+	s := &Schema{
+		Version:    version,
+		fields:     map[string]*Field{},
+		namespaces: map[string]bool{},
+		groups:     map[string]string{},
+	}
+
+	for i, c := range contents {
+		if isListFormat(c) {
+			var defs []listDefinition
+			if err := yaml.Unmarshal(c, &defs); err != nil {
+				return nil, fmt.Errorf("error parsing definitions %v: %v", i, err)
+			}
+			s.addListDefs("", defs)
+			continue
+		}
+
+		var defs map[string]definition
+		if err := yaml.Unmarshal(c, &defs); err != nil {
+			return nil, fmt.Errorf("error parsing definitions %v: %v", i, err)
+		}
+		s.addDefs("", defs)
+	}
+
+	for name := range s.fields {
+		for idx := strings.LastIndexByte(name, '.'); idx > 0; idx = strings.LastIndexByte(name, '.') {
+			name = name[:idx]
+			s.namespaces[name] = true
+		}
+	}
+	return s, nil
+}
+
+func (s *Schema) addDefs(path string, defs map[string]definition) {
+	for name, def := range defs {
+		if path != "" {
+			name = path + "." + name
+		}
+		name = normalizePath(name)
+
+		switch {
+		case name == "":
+		case def.Type == "group":
+			if def.Description != "" {
+				s.groups[name] = def.Description
+			}
+		default:
+			s.fields[name] = &Field{Name: name, Type: def.Type, Description: def.Description}
+		}
+		s.addDefs(name, def.Fields)
+	}
+}
+
+func (s *Schema) addListDefs(path string, defs []listDefinition) {
+	for _, def := range defs {
+		if def.Key != "" || def.Root {
+			s.addListDefs(path, def.Fields)
+			continue
+		}
+
+		name := def.Name
+		if path != "" {
+			name = path + "." + name
+		}
+		name = normalizePath(name)
+
+		switch {
+		case name == "":
+		case def.Type == "group":
+			if def.Description != "" {
+				s.groups[name] = def.Description
+			}
+		default:
+			typ := def.Type
+			if typ == "" {
+				typ = "keyword"
+			}
+			s.fields[name] = &Field{Name: name, Type: typ, Description: def.Description}
+		}
+		s.addListDefs(name, def.Fields)
+	}
+}
+
+// isListFormat checks if the yaml document is a list, by checking if the
+// first line that is not empty, a comment, or a document start marker starts
+// a sequence.
+func isListFormat(content []byte) bool {
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "---" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return line == "-" || strings.HasPrefix(line, "- ")
+	}
+	return false
+}
+
+// normalizePath removes the `base` group from field names.
+func normalizePath(name string) string {
+	if name == "base" {
+		return ""
+	}
+	return strings.TrimPrefix(name, "base.")
+}
+
+// Field returns the definition of the field with the fully qualified name.
+func (s *Schema) Field(name string) (*Field, bool) {
+	fld, ok := s.fields[name]
+	return fld, ok
+}
+
+// Lookup finds the definition a field is covered by. This is either the
+// field itself, or a parent field of type `object`, like `labels` for
+// `labels.env`. If the closest parent field is not of type `object`, the
+// parent field is returned with ok set to false.
+func (s *Schema) Lookup(name string) (*Field, bool) {
+	if fld, ok := s.fields[name]; ok {
+		return fld, true
+	}
+
+	for idx := strings.LastIndexByte(name, '.'); idx > 0; idx = strings.LastIndexByte(name, '.') {
+		name = name[:idx]
+		if fld, ok := s.fields[name]; ok {
+			return fld, fld.Type == "object"
+		}
+	}
+	return nil, false
+}
+
+// IsNamespace checks if name is the prefix of defined fields, like `host` for
+// `host.hostname`.
+func (s *Schema) IsNamespace(name string) bool {
+	return s.namespaces[name]
+}
+
+// GroupDescription returns the description of a field group, like `host`, if
+// the group is defined in the schema files.
+func (s *Schema) GroupDescription(name string) string {
+	return s.groups[name]
+}
+
+// Fields returns all field definitions, sorted by name.
+func (s *Schema) Fields() []*Field {
+	fields := make([]*Field, 0, len(s.fields))
+	for _, fld := range s.fields {
+		fields = append(fields, fld)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+	return fields
+}
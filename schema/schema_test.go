@@ -0,0 +1,165 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ecsSource follows the format of the ECS schemas/*.yml files.
+const ecsSource = `
+---
+# comment
+- name: base
+  root: true
+  title: Base
+  short: All fields defined directly at the top level
+  description: >
+    The base field set contains all fields which are on the top level.
+  type: group
+  fields:
+    - name: "@timestamp"
+      level: core
+      required: true
+      type: date
+      description: >
+        Date/time when the event originated.
+    - name: message
+      level: core
+      type: text
+      description: >
+        Log message.
+
+- name: host
+  title: Host
+  description: >
+    A host is defined as a general computing instance.
+  type: group
+  fields:
+    - name: hostname
+      level: core
+      type: keyword
+      description: >
+        Hostname of the host.
+    - name: os.name
+      level: extended
+      type: keyword
+      multi_fields:
+        - type: text
+          name: text
+`
+
+// beatsFields follows the format of the Beats fields.yml files.
+const beatsFields = `
+- key: myapp
+  title: My application
+  description: >
+    Fields reported by my application.
+  fields:
+    - name: myapp
+      type: group
+      description: >
+        Request handling.
+      fields:
+        - name: request_id
+          description: >
+            Unique ID of the request.
+        - name: duration
+          type: long
+    - name: labels.team
+      type: keyword
+`
+
+// ecsFlat follows the format of the ECS generated/ecs/ecs_flat.yml file.
+const ecsFlat = `
+"@timestamp":
+  name: "@timestamp"
+  flat_name: "@timestamp"
+  type: date
+host.hostname:
+  name: hostname
+  flat_name: host.hostname
+  type: keyword
+`
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		content string
+		fields  map[string]string // field name -> type
+		groups  map[string]string
+	}{
+		"ecs source": {
+			content: ecsSource,
+			fields: map[string]string{
+				"@timestamp":    "date",
+				"message":       "text",
+				"host.hostname": "keyword",
+				"host.os.name":  "keyword",
+			},
+			groups: map[string]string{
+				"host": "A host is defined as a general computing instance.\n",
+			},
+		},
+		"beats fields": {
+			content: beatsFields,
+			fields: map[string]string{
+				"myapp.request_id": "keyword",
+				"myapp.duration":   "long",
+				"labels.team":      "keyword",
+			},
+			groups: map[string]string{
+				"myapp": "Request handling.\n",
+			},
+		},
+		"ecs flat": {
+			content: ecsFlat,
+			fields: map[string]string{
+				"@timestamp":    "date",
+				"host.hostname": "keyword",
+			},
+			groups: map[string]string{},
+		},
+	}
+
+	for name, test := range cases {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			s, err := Parse("test", []byte(test.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fields := map[string]string{}
+			for _, fld := range s.Fields() {
+				fields[fld.Name] = fld.Type
+			}
+			if !reflect.DeepEqual(fields, test.fields) {
+				t.Errorf("got fields %v, want %v", fields, test.fields)
+			}
+			if !reflect.DeepEqual(s.groups, test.groups) {
+				t.Errorf("got groups %q, want %q", s.groups, test.groups)
+			}
+		})
+	}
+}
+
+func TestParseMixedFormats(t *testing.T) {
+	s, err := Parse("test", []byte(ecsSource), []byte(beatsFields))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"host.hostname", "myapp.duration"} {
+		if _, ok := s.Field(name); !ok {
+			t.Errorf("missing field %v", name)
+		}
+	}
+	if !s.IsNamespace("host.os") {
+		t.Error("expected host.os to be a namespace")
+	}
+}